@@ -3,7 +3,9 @@ package bridge
 import (
 	"context"
 	"errors"
+	"fmt"
 	client2 "github.com/richseviora/huego/internal/client"
+	"github.com/richseviora/huego/internal/store"
 	"github.com/richseviora/huego/pkg/logger"
 	"github.com/richseviora/huego/pkg/resources/client"
 )
@@ -12,6 +14,9 @@ type Builder struct {
 	FileLocation  string
 	BridgeManager *CacheManager
 	Logger        logger.Logger
+	// Strategies find bridge candidates when a cached address no longer answers with the expected ID. Defaults to
+	// DefaultStrategies.
+	Strategies []Strategy
 }
 
 func NewBuilderWithPath(fileLocation string, logger logger.Logger) (client.PersistentClientProvider, error) {
@@ -30,6 +35,28 @@ func NewBuilderWithPath(fileLocation string, logger logger.Logger) (client.Persi
 	return res, nil
 }
 
+// NewBuilderWithStore is NewBuilderWithPath with application keys kept in keys rather than in the cache file, for
+// example in a store.EncryptedKeyStore. Keys already in the cache file are moved to keys. Read-only stores such as
+// store.EnvKeyStore are rejected, as pairing would register a key with the bridge that could not be saved; use
+// NewClientWithAddressAndKey with a key read from them instead.
+func NewBuilderWithStore(fileLocation string, keys store.KeyStore, logger logger.Logger) (client.PersistentClientProvider, error) {
+	if store.IsReadOnly(keys) {
+		return nil, fmt.Errorf("cannot keep application keys in this store: %w", store.ErrReadOnly)
+	}
+	res := &Builder{
+		FileLocation:  fileLocation,
+		BridgeManager: NewCacheManager(fileLocation, logger, WithCacheKeyStore(keys)),
+		Logger:        logger,
+	}
+	if err := res.BridgeManager.Load(); err != nil {
+		res.Logger.Error("Failed to load cache", map[string]interface{}{
+			"error": err,
+		})
+		return nil, err
+	}
+	return res, nil
+}
+
 func NewBuilderWithoutPath(logger logger.Logger) (client.ClientProvider, error) {
 	return &Builder{
 		Logger: logger,
@@ -41,15 +68,20 @@ func (b *Builder) NewClientWithAddressAndKey(address string, key string) (client
 }
 
 func (b *Builder) NewClientWithNewBridge() (string, client.HueServiceClient, error) {
-	if b.FileLocation != "" {
+	if b.FileLocation == "" {
 		return "", nil, NoFileLocationError
 	}
 	bridge, err := b.BridgeManager.FindUnauthenticatedBridge()
 	if err != nil {
 		return "", nil, err
 	}
-	r := client2.NewBridgeRegistrationClient(bridge.InternalIPAddress, b.Logger)
-	key, err := r.RegisterDevice(context.Background(), "huego", "")
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultPairingTimeout)
+	defer cancel()
+	opts := []PairOption{WithPairingLogger(b.Logger)}
+	if b.BridgeManager.keys != nil {
+		opts = append(opts, WithPairingKeyStore(b.BridgeManager.keys))
+	}
+	credentials, err := PairBridge(ctx, bridge, "huego", "", nil, opts...)
 	if err != nil {
 		b.Logger.Error("Failed to register device", map[string]interface{}{
 			"error":    err,
@@ -58,6 +90,7 @@ func (b *Builder) NewClientWithNewBridge() (string, client.HueServiceClient, err
 		})
 		return "", nil, err
 	}
+	key := credentials.Username
 	b.Logger.Info("Registered device", map[string]interface{}{
 		"bridgeID": bridge.ID,
 	})
@@ -68,18 +101,68 @@ func (b *Builder) NewClientWithNewBridge() (string, client.HueServiceClient, err
 	b.Logger.Trace("Saved key", map[string]interface{}{
 		"bridgeID": bridge.ID,
 	})
-	return bridge.ID, client2.NewAPIClient(bridge.InternalIPAddress, key, b.Logger), nil
+	return bridge.ID, client2.NewAPIClient(bridge.Address(), key, b.Logger), nil
 }
 
+// NewClientWithExistingBridge creates a client for a cached bridge. The address is probed first so the key is only
+// sent to the bridge it belongs to; if the bridge moved or its cache entry expired, it is rediscovered and the cache
+// updated.
 func (b *Builder) NewClientWithExistingBridge(bridgeId string) (client.HueServiceClient, error) {
-	if b.FileLocation != "" {
+	if b.FileLocation == "" {
 		return nil, NoFileLocationError
 	}
+	bridge, key, err := b.existingBridge(context.Background(), bridgeId)
+	if err != nil {
+		return nil, err
+	}
+	return client2.NewAPIClient(bridge.Address(), key, b.Logger), nil
+}
+
+// existingBridge returns the cached bridge and key once the bridge has been confirmed at its address, updating the
+// cache if it had to be rediscovered.
+func (b *Builder) existingBridge(ctx context.Context, bridgeId string) (Bridge, string, error) {
 	bridge, key, err := b.BridgeManager.GetBridgeAndKey(bridgeId)
+	if errors.Is(err, NoBridgeFoundError) {
+		// The bridge entry may have expired while its key is still cached, in which case it is rediscovered.
+		key, err = b.BridgeManager.GetKey(bridgeId)
+		bridge = Bridge{ID: NormalizeBridgeID(bridgeId)}
+	}
+	if err != nil {
+		return Bridge{}, "", err
+	}
+	bridge, changed, err := b.resolveBridge(ctx, bridge)
 	if err != nil {
+		return Bridge{}, "", err
+	}
+	if changed {
+		if err := b.BridgeManager.SaveBridge(bridge); err != nil {
+			return Bridge{}, "", err
+		}
+	}
+	return bridge, key, nil
+}
+
+// RotateKey replaces the cached application key for the bridge with a new one and revokes the old key, returning a
+// client using the new key. The bridge is probed first, as for NewClientWithExistingBridge, so neither the pairing
+// request nor the old key go to another device that took over its address. The link button has to be pressed while it
+// runs. If only the revocation failed, the client is returned along with an error matching ErrRevokeFailed.
+func (b *Builder) RotateKey(ctx context.Context, bridgeId string, progress func(PairingProgress)) (client.HueServiceClient, error) {
+	if b.FileLocation == "" {
+		return nil, NoFileLocationError
+	}
+	bridge, oldKey, err := b.existingBridge(ctx, bridgeId)
+	if err != nil {
+		return nil, err
+	}
+	// The cache manager writes to its key store, if it has one, so no key store option is passed to RotateKey.
+	persist := func(c *Credentials) error {
+		return b.BridgeManager.SaveBridgeKeyForID(c.Username, bridge.ID)
+	}
+	credentials, err := RotateKey(ctx, bridge, oldKey, "huego", "", persist, progress, WithPairingLogger(b.Logger))
+	if credentials == nil {
 		return nil, err
 	}
-	return client2.NewAPIClient(bridge.InternalIPAddress, key, b.Logger), nil
+	return client2.NewAPIClient(bridge.Address(), credentials.Username, b.Logger), err
 }
 
 var (
@@ -1,56 +1,206 @@
 package bridge
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/richseviora/huego/internal/store"
 	"github.com/richseviora/huego/pkg/logger"
 	"os"
+	"path/filepath"
+	"sync"
 	"time"
 )
 
+// bridgeCacheFile is used when the cache manager is created without a file location.
 const bridgeCacheFile = "bridge_cache.json"
 
+// CurrentCacheVersion is the schema version written by Save. Files with an older version are migrated on Load.
+const CurrentCacheVersion = 2
+
+// DefaultCacheTTL is how long discovered bridge addresses are trusted before they are dropped on Load. Application
+// keys never expire.
+const DefaultCacheTTL = time.Hour * 24 * 30
+
+// cacheMigrations upgrade a cache from version i to version i+1.
+var cacheMigrations = []func(*BridgeCache){
+	// Version 0 had no version field and stored bridge IDs as they were reported, in either case.
+	func(c *BridgeCache) {
+		bridges := make(map[string]Bridge, len(c.Bridges))
+		for _, b := range c.Bridges {
+			b.ID = NormalizeBridgeID(b.ID)
+			bridges[b.ID] = b
+		}
+		c.Bridges = bridges
+		keys := make(map[string]string, len(c.ApplicationKeys))
+		for id, key := range c.ApplicationKeys {
+			keys[NormalizeBridgeID(id)] = key
+		}
+		c.ApplicationKeys = keys
+	},
+	// Version 1 only had the cache-wide timestamp, which every bridge inherits.
+	func(c *BridgeCache) {
+		c.BridgeTimestamps = make(map[string]time.Time, len(c.Bridges))
+		for id := range c.Bridges {
+			c.BridgeTimestamps[id] = c.Timestamp
+		}
+	},
+}
+
+var ErrUnsupportedCacheVersion = errors.New("unsupported cache version")
+
 type BridgeCache struct {
-	Bridges   map[string]Bridge `json:"bridges"`
-	Timestamp time.Time         `json:"timestamp"`
-	// Maps bridge IDs to application keys.
+	Version int               `json:"version"`
+	Bridges map[string]Bridge `json:"bridges"`
+	// Timestamp is when bridges were last discovered.
+	Timestamp time.Time `json:"timestamp"`
+	// BridgeTimestamps maps bridge IDs to when the entry was last discovered or confirmed, for expiry.
+	BridgeTimestamps map[string]time.Time `json:"bridge_timestamps"`
+	// Maps bridge IDs to application keys. Unused when the cache manager has a key store.
 	ApplicationKeys map[string]string `json:"application_keys"`
 }
 
+func newBridgeCache() *BridgeCache {
+	return &BridgeCache{
+		Version:          CurrentCacheVersion,
+		Bridges:          make(map[string]Bridge),
+		BridgeTimestamps: make(map[string]time.Time),
+		ApplicationKeys:  make(map[string]string),
+	}
+}
+
+// CacheManager persists discovered bridges and their application keys. It is safe for concurrent use.
 type CacheManager struct {
+	mu           sync.RWMutex
 	cache        *BridgeCache
 	fileLocation string
+	ttl          time.Duration
+	keys         store.KeyStore
 	logger       logger.Logger
 }
 
-func NewCacheManager(fileLocation string, l logger.Logger) *CacheManager {
-	return &CacheManager{
+// CacheOption defines functional options for NewCacheManager.
+type CacheOption func(*CacheManager)
+
+// WithCacheTTL overrides DefaultCacheTTL. A TTL of zero keeps bridge entries forever.
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return func(c *CacheManager) {
+		c.ttl = ttl
+	}
+}
+
+// WithCacheKeyStore keeps application keys in s under UsernameStoreKey instead of in the cache file. Keys found in the
+// cache file are moved to s on Load.
+func WithCacheKeyStore(s store.KeyStore) CacheOption {
+	return func(c *CacheManager) {
+		c.keys = s
+	}
+}
+
+func NewCacheManager(fileLocation string, l logger.Logger, opts ...CacheOption) *CacheManager {
+	if fileLocation == "" {
+		fileLocation = bridgeCacheFile
+	}
+	c := &CacheManager{
+		cache:        newBridgeCache(),
 		fileLocation: fileLocation,
+		ttl:          DefaultCacheTTL,
 		logger:       l,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
+// Load reads the cache file, migrating it to CurrentCacheVersion and dropping bridge entries older than the TTL. A
+// missing file leaves the cache empty. Entries that expire later are ignored by lookups until they are saved again.
 func (c *CacheManager) Load() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.logger.Trace("loading cache from file", map[string]interface{}{
 		"file": c.fileLocation,
 	})
-	data, err := os.ReadFile(bridgeCacheFile)
+	data, err := os.ReadFile(c.fileLocation)
+	if errors.Is(err, os.ErrNotExist) {
+		c.logger.Trace("cache file not found, starting empty", map[string]interface{}{
+			"file": c.fileLocation,
+		})
+		c.cache = newBridgeCache()
+		return nil
+	}
 	if err != nil {
 		c.logger.Error("failed to read cache file", map[string]interface{}{
 			"error": err,
 		})
 		return err
 	}
-	if err := json.Unmarshal(data, c.cache); err != nil {
+	cache := newBridgeCache()
+	cache.Version = 0
+	if err := json.Unmarshal(data, cache); err != nil {
 		c.logger.Error("failed to unmarshal JSON", map[string]interface{}{
 			"error": err,
 		})
 		return err
 	}
+	if cache.Version > CurrentCacheVersion {
+		return fmt.Errorf("%w: %d, expected at most %d", ErrUnsupportedCacheVersion, cache.Version, CurrentCacheVersion)
+	}
+	if cache.Bridges == nil {
+		cache.Bridges = make(map[string]Bridge)
+	}
+	if cache.ApplicationKeys == nil {
+		cache.ApplicationKeys = make(map[string]string)
+	}
+	for ; cache.Version < CurrentCacheVersion; cache.Version++ {
+		c.logger.Info("migrating cache", map[string]interface{}{
+			"file":    c.fileLocation,
+			"version": cache.Version + 1,
+		})
+		cacheMigrations[cache.Version](cache)
+	}
+	if cache.BridgeTimestamps == nil {
+		cache.BridgeTimestamps = make(map[string]time.Time)
+	}
+	c.cache = cache
+	for id := range cache.Bridges {
+		if c.expired(id) {
+			c.logger.Info("cached bridge expired, it will be rediscovered", map[string]interface{}{
+				"bridgeID":  id,
+				"timestamp": cache.BridgeTimestamps[id],
+			})
+			delete(cache.Bridges, id)
+			delete(cache.BridgeTimestamps, id)
+		}
+	}
+	if c.keys != nil && len(cache.ApplicationKeys) > 0 {
+		c.logger.Info("moving application keys to key store", map[string]interface{}{
+			"file": c.fileLocation,
+		})
+		for id, key := range cache.ApplicationKeys {
+			if err := c.keys.Set(UsernameStoreKey(id), key); err != nil {
+				return err
+			}
+		}
+		cache.ApplicationKeys = make(map[string]string)
+		if err := c.save(); err != nil {
+			return err
+		}
+	}
 	c.logger.Trace("cache loaded", map[string]interface{}{})
 	return nil
 }
 
 func (c *CacheManager) Save() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.save()
+}
+
+// save writes the cache to a temporary file readable only by the owner and renames it over the cache file, so a
+// crash never leaves a partially written file. Callers must hold c.mu.
+func (c *CacheManager) save() error {
 	c.logger.Trace("saving cache to file", map[string]interface{}{
 		"file": c.fileLocation,
 	})
@@ -58,61 +208,125 @@ func (c *CacheManager) Save() error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(bridgeCacheFile, data, 0644)
+	f, err := os.CreateTemp(filepath.Dir(c.fileLocation), "."+filepath.Base(c.fileLocation)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := f.Chmod(0600); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), c.fileLocation)
 }
 
 func (c *CacheManager) GetBridgeAndKey(id string) (Bridge, string, error) {
-	var bridge Bridge
-	found := false
-	for _, b := range c.cache.Bridges {
-		if b.ID == id {
-			bridge = b
-			found = true
-			break
-		}
-	}
-	if !found {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	bridge, found := c.cache.Bridges[NormalizeBridgeID(id)]
+	if !found || c.expired(bridge.ID) {
 		return Bridge{}, "", NoBridgeFoundError
 	}
-	result, ok := c.cache.ApplicationKeys[id]
+	result, ok := c.key(bridge.ID)
 	if !ok {
 		return Bridge{}, "", NoKeyForBridgeError
 	}
 	return bridge, result, nil
 }
 
-func (c *CacheManager) UpdateBridgeData() error {
-	newBridges, err := DiscoverBridges(c.logger)
+// expired reports whether the bridge entry is older than the TTL. Callers must hold c.mu.
+func (c *CacheManager) expired(id string) bool {
+	return c.ttl > 0 && time.Since(c.cache.BridgeTimestamps[id]) > c.ttl
+}
+
+// key returns the application key from the key store, or from the cache file if there is none. Callers must hold c.mu.
+func (c *CacheManager) key(id string) (string, bool) {
+	if c.keys == nil {
+		result, ok := c.cache.ApplicationKeys[id]
+		return result, ok
+	}
+	value, err := c.keys.Get(UsernameStoreKey(id))
 	if err != nil {
-		return err
+		return "", false
 	}
+	result, ok := value.(string)
+	return result, ok
+}
 
-	if c.cache == nil {
-		c.cache = &BridgeCache{}
-	}
-	if c.cache.Bridges == nil {
-		c.cache.Bridges = make(map[string]Bridge)
+// GetKey returns the application key for the bridge, which is kept even after the bridge entry expired.
+func (c *CacheManager) GetKey(id string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	result, ok := c.key(NormalizeBridgeID(id))
+	if !ok {
+		return "", NoKeyForBridgeError
 	}
-	if c.cache.ApplicationKeys == nil {
-		c.cache.ApplicationKeys = make(map[string]string)
+	return result, nil
+}
+
+func (c *CacheManager) UpdateBridgeData() error {
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+	return c.UpdateBridgeDataContext(ctx, DefaultStrategies(c.logger)...)
+}
+
+// UpdateBridgeDataContext adds the bridges found by the strategies to the cache.
+func (c *CacheManager) UpdateBridgeDataContext(ctx context.Context, strategies ...Strategy) error {
+	newBridges, err := Discover(ctx, c.logger, strategies...)
+	if err != nil {
+		return err
 	}
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
 	for _, newBridge := range newBridges {
 		c.cache.Bridges[newBridge.ID] = newBridge
+		c.cache.BridgeTimestamps[newBridge.ID] = now
 	}
+	c.cache.Timestamp = now
+	return c.save()
+}
 
-	c.cache.Timestamp = time.Now()
-	return c.Save()
+// SaveBridge replaces the cached entry with the same ID, for example after the bridge's address changed.
+func (c *CacheManager) SaveBridge(bridge Bridge) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	bridge.ID = NormalizeBridgeID(bridge.ID)
+	c.cache.Bridges[bridge.ID] = bridge
+	c.cache.BridgeTimestamps[bridge.ID] = time.Now()
+	return c.save()
 }
 
 func (c *CacheManager) SaveBridgeKeyForID(key, bridgeId string) error {
-	c.cache.ApplicationKeys[bridgeId] = key
-	return c.Save()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.keys != nil {
+		return c.keys.Set(UsernameStoreKey(NormalizeBridgeID(bridgeId)), key)
+	}
+	c.cache.ApplicationKeys[NormalizeBridgeID(bridgeId)] = key
+	return c.save()
 }
 
 func (c *CacheManager) FindUnauthenticatedBridge() (Bridge, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	for id := range c.cache.Bridges {
-		_, exists := c.cache.ApplicationKeys[id]
+		if c.expired(id) {
+			continue
+		}
+		_, exists := c.key(id)
 		if !exists {
 			return c.cache.Bridges[id], nil
 		}
@@ -0,0 +1,199 @@
+package bridge
+
+import (
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/richseviora/huego/internal/store"
+	"github.com/richseviora/huego/pkg/logger"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCacheManager_LoadMissingFile(t *testing.T) {
+	c := NewCacheManager(filepath.Join(t.TempDir(), "cache.json"), logger.NoopLogger{})
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.FindUnauthenticatedBridge(); !errors.Is(err, NoUnauthenticatedBridgeFoundError) {
+		t.Errorf("FindUnauthenticatedBridge() error = %v, want %v", err, NoUnauthenticatedBridgeFoundError)
+	}
+}
+
+func TestCacheManager_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	c := NewCacheManager(path, logger.NoopLogger{})
+	bridge := Bridge{ID: "ecb5fafffe111111", InternalIPAddress: "192.168.1.2"}
+	if err := c.SaveBridge(bridge); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SaveBridgeKeyForID("key", "ECB5FAFFFE111111"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	loaded := NewCacheManager(path, logger.NoopLogger{})
+	if err := loaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	result, key, err := loaded.GetBridgeAndKey("ecb5fafffe111111")
+	if err != nil || key != "key" || !cmp.Equal(result, bridge) {
+		t.Errorf("GetBridgeAndKey() = %v, %s, %v, want %v, key, nil", result, key, err, bridge)
+	}
+}
+
+func TestCacheManager_Load(t *testing.T) {
+	recent := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	stale := time.Now().Add(-DefaultCacheTTL - time.Hour).Format(time.RFC3339)
+	tests := []struct {
+		name       string
+		data       string
+		wantBridge bool
+		wantErr    error
+	}{
+		{
+			name:       "migrates unversioned IDs",
+			data:       `{"bridges":{"ECB5FAFFFE111111":{"id":"ECB5FAFFFE111111","internalipaddress":"192.168.1.2"}},"timestamp":"` + recent + `","application_keys":{"ECB5FAFFFE111111":"key"}}`,
+			wantBridge: true,
+		},
+		{
+			name: "expires stale bridges but keeps keys",
+			data: `{"version":1,"bridges":{"ecb5fafffe111111":{"id":"ecb5fafffe111111","internalipaddress":"192.168.1.2"}},"timestamp":"` + stale + `","application_keys":{"ecb5fafffe111111":"key"}}`,
+		},
+		{
+			name:    "rejects newer versions",
+			data:    `{"version":` + strconv.Itoa(CurrentCacheVersion+1) + `}`,
+			wantErr: ErrUnsupportedCacheVersion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cache.json")
+			if err := os.WriteFile(path, []byte(tt.data), 0600); err != nil {
+				t.Fatal(err)
+			}
+			c := NewCacheManager(path, logger.NoopLogger{})
+			err := c.Load()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Load() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			_, _, err = c.GetBridgeAndKey("ecb5fafffe111111")
+			if (err == nil) != tt.wantBridge {
+				t.Errorf("GetBridgeAndKey() error = %v, want bridge %v", err, tt.wantBridge)
+			}
+			if key, err := c.GetKey("ecb5fafffe111111"); err != nil || key != "key" {
+				t.Errorf("GetKey() = %s, %v, want key, nil", key, err)
+			}
+		})
+	}
+}
+
+func TestCacheManager_ConcurrentSaves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	c := NewCacheManager(path, logger.NoopLogger{})
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := c.SaveBridgeKeyForID("key", strconv.Itoa(i)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	loaded := NewCacheManager(path, logger.NoopLogger{})
+	if err := loaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		if _, err := loaded.GetKey(strconv.Itoa(i)); err != nil {
+			t.Errorf("GetKey(%d) error = %v", i, err)
+		}
+	}
+}
+
+func TestCacheManager_KeyStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	data := `{"version":1,"bridges":{"ecb5fafffe111111":{"id":"ecb5fafffe111111"}},"timestamp":"` + time.Now().Format(time.RFC3339) + `","application_keys":{"ecb5fafffe111111":"key"}}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	keys := store.NewMemoryKeyStore()
+	c := NewCacheManager(path, logger.NoopLogger{}, WithCacheKeyStore(keys))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := keys.Get(UsernameStoreKey("ecb5fafffe111111")); err != nil || value != "key" {
+		t.Errorf("keys.Get() = %v, %v, want key, nil", value, err)
+	}
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(saved), `"key"`) {
+		t.Errorf("cache file still contains the application key: %s", saved)
+	}
+	if _, key, err := c.GetBridgeAndKey("ecb5fafffe111111"); err != nil || key != "key" {
+		t.Errorf("GetBridgeAndKey() = %s, %v, want key, nil", key, err)
+	}
+}
+
+func TestNewBuilderWithStore_RejectsReadOnlyStore(t *testing.T) {
+	_, err := NewBuilderWithStore(filepath.Join(t.TempDir(), "cache.json"), store.NewEnvKeyStore(""), logger.NoopLogger{})
+	if !errors.Is(err, store.ErrReadOnly) {
+		t.Errorf("NewBuilderWithStore() error = %v, want %v", err, store.ErrReadOnly)
+	}
+}
+
+func TestCacheManager_ExpiresEntriesIndividually(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	stale := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	data := `{"version":2,"bridges":{"ecb5fafffe111111":{"id":"ecb5fafffe111111"},"ecb5fafffe222222":{"id":"ecb5fafffe222222"}},` +
+		`"bridge_timestamps":{"ecb5fafffe111111":"` + stale + `","ecb5fafffe222222":"` + time.Now().Format(time.RFC3339) + `"},` +
+		`"application_keys":{"ecb5fafffe111111":"key1","ecb5fafffe222222":"key2"}}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := NewCacheManager(path, logger.NoopLogger{}, WithCacheTTL(time.Minute*30))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.GetBridgeAndKey("ecb5fafffe111111"); !errors.Is(err, NoBridgeFoundError) {
+		t.Errorf("GetBridgeAndKey() error = %v, want %v for the stale bridge", err, NoBridgeFoundError)
+	}
+	// Saving one bridge must not refresh the other.
+	if err := c.SaveBridge(Bridge{ID: "ecb5fafffe222222"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.GetBridgeAndKey("ecb5fafffe111111"); !errors.Is(err, NoBridgeFoundError) {
+		t.Errorf("GetBridgeAndKey() error = %v after saving another bridge, want %v", err, NoBridgeFoundError)
+	}
+
+	// Entries also expire while the process keeps running.
+	c = NewCacheManager(filepath.Join(t.TempDir(), "cache.json"), logger.NoopLogger{}, WithCacheTTL(time.Millisecond))
+	if err := c.SaveBridge(Bridge{ID: "ecb5fafffe333333"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SaveBridgeKeyForID("key3", "ecb5fafffe333333"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, _, err := c.GetBridgeAndKey("ecb5fafffe333333"); !errors.Is(err, NoBridgeFoundError) {
+		t.Errorf("GetBridgeAndKey() error = %v, want %v once the TTL passed", err, NoBridgeFoundError)
+	}
+}
@@ -2,79 +2,185 @@ package bridge
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"github.com/grandcat/zeroconf"
+	"errors"
 	"github.com/richseviora/huego/pkg/logger"
-	"net/http"
+	"net"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 )
 
-const url = "https://discovery.meethue.com"
-const discoveryTimeout = time.Second * 5
+// discoveryTimeout bounds DiscoverBridges, leaving room for the cloud and subnet scan fallbacks after mDNS.
+const discoveryTimeout = time.Second * 20
 
-func DiscoverBridgesWithMDNS(l logger.Logger) ([]Bridge, error) {
-	resolver, err := zeroconf.NewResolver(nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize resolver: %v", err)
-	}
+// Strategy is one way of finding bridges. Discover sends every bridge it finds to found as soon as it is found and
+// returns when ctx is done or the strategy has nothing more to report.
+type Strategy interface {
+	Name() string
+	Discover(ctx context.Context, found chan<- Bridge) error
+}
 
-	entries := make(chan *zeroconf.ServiceEntry)
-	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
-	defer cancel()
+// DefaultStrategies tries mDNS first and only asks the cloud endpoint, which is rate limited, when mDNS finds nothing.
+// The local subnets are scanned as a last resort.
+func DefaultStrategies(l logger.Logger) []Strategy {
+	return []Strategy{Fallback(&MDNSStrategy{Logger: l}, &CloudStrategy{}, &SubnetScanStrategy{Logger: l})}
+}
 
+// Stream runs the strategies concurrently and sends each bridge the first time it is seen, until all strategies finish
+// or ctx is done, then closes the channel. Results for the same bridge from different strategies are merged, so a
+// bridge is sent once even if it was found several times.
+func Stream(ctx context.Context, l logger.Logger, strategies ...Strategy) <-chan Bridge {
+	out := make(chan Bridge)
 	go func() {
-		err = resolver.Browse(ctx, "_hue._tcp", "local.", entries)
-		if err != nil {
-			l.Error("Failed to browse for bridges", map[string]interface{}{
-				"error": err,
-			})
+		defer close(out)
+		m := &merger{}
+		for b := range run(ctx, l, strategies) {
+			b, isNew := m.add(b)
+			if !isNew {
+				continue
+			}
+			select {
+			case out <- b:
+			case <-ctx.Done():
+				return
+			}
 		}
 	}()
+	return out
+}
 
-	var bridges []Bridge
-	for entry := range entries {
-		port := entry.Port
-		for _, ip := range entry.AddrIPv4 {
-			bridges = append(bridges, Bridge{
-				ID:                entry.Instance,
-				InternalIPAddress: ip.String(),
-				Port:              port,
-			})
-		}
+// Discover runs the strategies concurrently and returns every bridge found, merged by ID. Bridges found by several
+// strategies are returned once with all their addresses.
+func Discover(ctx context.Context, l logger.Logger, strategies ...Strategy) ([]Bridge, error) {
+	m := &merger{}
+	var errs []error
+	var mu sync.Mutex
+	results := runWithErrors(ctx, l, strategies, func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	})
+	for b := range results {
+		m.add(b)
+	}
+	if len(m.bridges) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
 	}
+	return m.bridges, nil
+}
 
-	return bridges, nil
+func run(ctx context.Context, l logger.Logger, strategies []Strategy) <-chan Bridge {
+	return runWithErrors(ctx, l, strategies, nil)
 }
 
-func DiscoverBridges(logger logger.Logger) ([]Bridge, error) {
-	var bridges []Bridge
-	// Try mDNS discovery first
-	if bridges, err := DiscoverBridgesWithMDNS(logger); err == nil && len(bridges) > 0 {
-		logger.Info("Found Bridges via MDNS", map[string]interface{}{
-			"bridges": len(bridges),
-		})
-		return bridges, nil
+func runWithErrors(ctx context.Context, l logger.Logger, strategies []Strategy, onError func(error)) <-chan Bridge {
+	found := make(chan Bridge)
+	var wg sync.WaitGroup
+	for _, s := range strategies {
+		wg.Add(1)
+		go func(s Strategy) {
+			defer wg.Done()
+			if err := s.Discover(ctx, found); err != nil && ctx.Err() == nil {
+				l.Warn("Bridge discovery strategy failed", map[string]interface{}{
+					"strategy": s.Name(),
+					"error":    err,
+				})
+				if onError != nil {
+					onError(err)
+				}
+			}
+		}(s)
 	}
+	go func() {
+		wg.Wait()
+		close(found)
+	}()
+	return found
+}
 
-	// Fallback to HTTP discovery if mDNS fails
+// merger de-duplicates bridges by normalised ID, or by address for results without a full ID.
+type merger struct {
+	bridges []Bridge
+}
 
-	resp, err := http.Get(url)
-	if err != nil {
-		logger.Error("failed to load bridge via external discovery", map[string]interface{}{
-			"error": err,
-		})
-		return nil, err
+// add merges b into the known bridges. It returns b with its ID normalised, and whether it is a bridge not seen
+// before.
+func (m *merger) add(b Bridge) (Bridge, bool) {
+	b.ID = NormalizeBridgeID(b.ID)
+	b.Addresses = appendAddress(b.Addresses, b.InternalIPAddress)
+	for i := range m.bridges {
+		existing := &m.bridges[i]
+		sameID := IsFullBridgeID(b.ID) && existing.ID == b.ID
+		sharedAddress := false
+		for _, a := range b.Addresses {
+			if slices.Contains(existing.Addresses, a) {
+				sharedAddress = true
+				break
+			}
+		}
+		if !sameID && !(sharedAddress && (!IsFullBridgeID(b.ID) || !IsFullBridgeID(existing.ID))) {
+			continue
+		}
+		if !IsFullBridgeID(existing.ID) && IsFullBridgeID(b.ID) {
+			existing.ID = b.ID
+		}
+		for _, a := range b.Addresses {
+			existing.Addresses = appendAddress(existing.Addresses, a)
+		}
+		if existing.Port == 0 {
+			existing.Port = b.Port
+		}
+		if isIPv6(existing.InternalIPAddress) && !isIPv6(b.InternalIPAddress) && b.InternalIPAddress != "" {
+			existing.InternalIPAddress = b.InternalIPAddress
+		}
+		return b, false
 	}
-	defer resp.Body.Close()
+	m.bridges = append(m.bridges, b)
+	return b, true
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(&bridges); err != nil {
-		return nil, err
+func appendAddress(addresses []string, address string) []string {
+	if address == "" || slices.Contains(addresses, address) {
+		return addresses
 	}
+	return append(addresses, address)
+}
 
-	logger.Info("Found Bridges via HTTP", map[string]interface{}{
-		"bridges": len(bridges),
-	})
+func isIPv6(address string) bool {
+	ip := net.ParseIP(address)
+	return ip != nil && ip.To4() == nil
+}
 
-	return bridges, nil
+// NormalizeBridgeID lower-cases the ID. mDNS instance names such as "Hue Bridge - 1A2B3C" are returned lower-cased
+// too, but are not full IDs; see IsFullBridgeID.
+func NormalizeBridgeID(id string) string {
+	return strings.ToLower(strings.TrimSpace(id))
+}
+
+// IsFullBridgeID reports whether id is a 16 character hexadecimal bridge ID, as reported by /api/0/config.
+func IsFullBridgeID(id string) bool {
+	if len(id) != 16 {
+		return false
+	}
+	for _, r := range strings.ToLower(id) {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// DiscoverBridgesWithMDNS finds bridges on the local network with mDNS.
+func DiscoverBridgesWithMDNS(l logger.Logger) ([]Bridge, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+	return Discover(ctx, l, &MDNSStrategy{Logger: l})
+}
+
+// DiscoverBridges finds bridges with DefaultStrategies, giving up after twenty seconds.
+func DiscoverBridges(l logger.Logger) ([]Bridge, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+	return Discover(ctx, l, DefaultStrategies(l)...)
 }
@@ -0,0 +1,99 @@
+package bridge
+
+import (
+	"context"
+	"github.com/google/go-cmp/cmp"
+	"github.com/richseviora/huego/pkg/logger"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// staticStrategy reports a fixed list of bridges.
+type staticStrategy []Bridge
+
+func (s staticStrategy) Name() string {
+	return "static"
+}
+
+func (s staticStrategy) Discover(ctx context.Context, found chan<- Bridge) error {
+	for _, b := range s {
+		if !send(ctx, found, b) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func TestDiscover_MergesDuplicates(t *testing.T) {
+	mdns := staticStrategy{
+		{ID: "ECB5FAFFFE000001", InternalIPAddress: "fe80::1", Port: 443},
+		{ID: "ecb5fafffe000001", InternalIPAddress: "192.168.1.2", Port: 443},
+		{ID: "Hue Bridge - 000002", InternalIPAddress: "192.168.1.3", Port: 443},
+	}
+	cloud := staticStrategy{
+		{ID: "ecb5fafffe000002", InternalIPAddress: "192.168.1.3", Port: 443},
+	}
+	bridges, err := Discover(context.Background(), logger.NoopLogger{}, mdns, cloud)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]Bridge{
+		"ecb5fafffe000001": {ID: "ecb5fafffe000001", InternalIPAddress: "192.168.1.2", Port: 443, Addresses: []string{"fe80::1", "192.168.1.2"}},
+		"ecb5fafffe000002": {ID: "ecb5fafffe000002", InternalIPAddress: "192.168.1.3", Port: 443, Addresses: []string{"192.168.1.3"}},
+	}
+	got := make(map[string]Bridge)
+	for _, b := range bridges {
+		got[b.ID] = b
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestStream_CloudFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id":"ecb5fafffe000003","internalipaddress":"192.168.1.4","port":443}]`))
+	}))
+	defer server.Close()
+
+	strategy := Fallback(staticStrategy{}, &CloudStrategy{URL: server.URL})
+	var got []Bridge
+	for b := range Stream(context.Background(), logger.NoopLogger{}, strategy) {
+		got = append(got, b)
+	}
+	expected := []Bridge{{ID: "ecb5fafffe000003", InternalIPAddress: "192.168.1.4", Port: 443, Addresses: []string{"192.168.1.4"}}}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseSSDPResponse(t *testing.T) {
+	response := "HTTP/1.1 200 OK\r\n" +
+		"LOCATION: http://192.168.1.5:80/description.xml\r\n" +
+		"hue-bridgeid: ECB5FAFFFE000005\r\n\r\n"
+	b, ok := parseSSDPResponse([]byte(response), &net.UDPAddr{IP: net.IPv4(192, 168, 1, 5)})
+	if !ok || b.ID != "ECB5FAFFFE000005" || b.InternalIPAddress != "192.168.1.5" {
+		t.Errorf("parseSSDPResponse() = %v, %v", b, ok)
+	}
+	if _, ok := parseSSDPResponse([]byte("HTTP/1.1 200 OK\r\nST: upnp:rootdevice\r\n\r\n"), nil); ok {
+		t.Errorf("parseSSDPResponse() accepted a response without hue-bridgeid")
+	}
+}
+
+func TestBridge_Address(t *testing.T) {
+	tests := []struct {
+		bridge   Bridge
+		expected string
+	}{
+		{Bridge{InternalIPAddress: "192.168.1.2", Port: 443}, "192.168.1.2"},
+		{Bridge{InternalIPAddress: "fe80::1"}, "[fe80::1]"},
+		{Bridge{InternalIPAddress: "fe80::1", Port: 8443}, "[fe80::1]:8443"},
+	}
+	for _, tt := range tests {
+		if got := tt.bridge.Address(); got != tt.expected {
+			t.Errorf("Address() = %s, want %s", got, tt.expected)
+		}
+	}
+}
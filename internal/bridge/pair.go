@@ -0,0 +1,141 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	client2 "github.com/richseviora/huego/internal/client"
+	"github.com/richseviora/huego/internal/store"
+	"github.com/richseviora/huego/pkg/logger"
+	"time"
+)
+
+// DefaultPairingPollInterval is how often PairBridge retries while waiting for the link button.
+const DefaultPairingPollInterval = time.Second
+
+// DefaultPairingTimeout is how long the builder waits for the link button. The bridge accepts registrations for 30
+// seconds after it is pressed.
+const DefaultPairingTimeout = time.Second * 60
+
+type PairingStage string
+
+const (
+	// PairingWaiting means the link button has not been pressed yet.
+	PairingWaiting PairingStage = "waiting_for_link_button"
+	// PairingRetrying means the attempt failed for a reason that may clear up, such as the bridge being busy.
+	PairingRetrying PairingStage = "retrying"
+	PairingComplete PairingStage = "complete"
+)
+
+// PairingProgress is reported after every registration attempt.
+type PairingProgress struct {
+	Stage   PairingStage
+	Attempt int
+	Elapsed time.Duration
+	// Err is the error of the last attempt, nil once pairing completes.
+	Err error
+}
+
+// Credentials are what the bridge returns once the link button is pressed.
+type Credentials struct {
+	BridgeID string
+	// Username is the application key sent with every API request.
+	Username string
+	// ClientKey is the pre-shared key for entertainment streaming.
+	ClientKey string
+}
+
+type pairOptions struct {
+	pollInterval time.Duration
+	keyStore     store.KeyStore
+	logger       logger.Logger
+}
+
+// PairOption defines functional options for PairBridge.
+type PairOption func(*pairOptions)
+
+// WithPairingPollInterval overrides DefaultPairingPollInterval.
+func WithPairingPollInterval(interval time.Duration) PairOption {
+	return func(o *pairOptions) {
+		o.pollInterval = interval
+	}
+}
+
+// WithPairingKeyStore saves the credentials to s under UsernameStoreKey and ClientKeyStoreKey once pairing completes.
+func WithPairingKeyStore(s store.KeyStore) PairOption {
+	return func(o *pairOptions) {
+		o.keyStore = s
+	}
+}
+
+func WithPairingLogger(l logger.Logger) PairOption {
+	return func(o *pairOptions) {
+		o.logger = l
+	}
+}
+
+// UsernameStoreKey is the key store entry holding the application key for the bridge.
+func UsernameStoreKey(bridgeID string) string {
+	return bridgeID + "/username"
+}
+
+// ClientKeyStoreKey is the key store entry holding the entertainment client key for the bridge.
+func ClientKeyStoreKey(bridgeID string) string {
+	return bridgeID + "/clientkey"
+}
+
+// PairBridge registers an application with the bridge, retrying until the link button is pressed or ctx is done.
+// progress, if not nil, is called after every attempt. Errors the bridge reports that retrying cannot fix, such as an
+// invalid device type, end pairing immediately.
+func PairBridge(ctx context.Context, bridge Bridge, appName, instanceName string, progress func(PairingProgress), opts ...PairOption) (*Credentials, error) {
+	options := pairOptions{pollInterval: DefaultPairingPollInterval, logger: logger.NoopLogger{}}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if progress == nil {
+		progress = func(PairingProgress) {}
+	}
+	c := client2.NewBridgeRegistrationClient(baseURL(bridge.Address()), options.logger)
+	start := time.Now()
+	// lastErr is the last error of an attempt that reached the bridge, so a timeout still says why pairing failed.
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		result, err := c.Register(ctx, appName, instanceName)
+		if err == nil {
+			credentials := &Credentials{BridgeID: bridge.ID, Username: result.Username, ClientKey: result.ClientKey}
+			if options.keyStore != nil {
+				if err := storeCredentials(options.keyStore, credentials); err != nil {
+					return credentials, err
+				}
+			}
+			progress(PairingProgress{Stage: PairingComplete, Attempt: attempt, Elapsed: time.Since(start)})
+			return credentials, nil
+		}
+		if ctx.Err() != nil {
+			return nil, errors.Join(ctx.Err(), lastErr)
+		}
+		lastErr = err
+		stage := PairingRetrying
+		var registrationErr *client2.RegistrationError
+		if errors.As(err, &registrationErr) {
+			if !registrationErr.Temporary() {
+				return nil, err
+			}
+			if errors.Is(err, client2.LinkButtonNotPressedError) {
+				stage = PairingWaiting
+			}
+		}
+		progress(PairingProgress{Stage: stage, Attempt: attempt, Elapsed: time.Since(start), Err: err})
+		select {
+		case <-ctx.Done():
+			return nil, errors.Join(ctx.Err(), lastErr)
+		case <-time.After(options.pollInterval):
+		}
+	}
+}
+
+func storeCredentials(s store.KeyStore, credentials *Credentials) error {
+	if err := s.Set(UsernameStoreKey(credentials.BridgeID), credentials.Username); err != nil {
+		return err
+	}
+	return s.Set(ClientKeyStoreKey(credentials.BridgeID), credentials.ClientKey)
+}
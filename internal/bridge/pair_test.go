@@ -0,0 +1,94 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	client2 "github.com/richseviora/huego/internal/client"
+	"github.com/richseviora/huego/internal/store"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const linkButtonNotPressed = `[{"error":{"type":101,"address":"","description":"link button not pressed"}}]`
+
+func registrationServer(responses ...string) *httptest.Server {
+	attempt := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := responses[min(attempt, len(responses)-1)]
+		attempt++
+		_, _ = w.Write([]byte(response))
+	}))
+}
+
+func TestPairBridge(t *testing.T) {
+	server := registrationServer(
+		linkButtonNotPressed,
+		linkButtonNotPressed,
+		`[{"success":{"username":"app-key","clientkey":"client-key"}}]`,
+	)
+	defer server.Close()
+	keyStore, err := store.NewDiskKeyStore(filepath.Join(t.TempDir(), "keys.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stages []PairingStage
+	credentials, err := PairBridge(context.Background(), Bridge{ID: "b1", InternalIPAddress: server.URL}, "app", "test",
+		func(p PairingProgress) {
+			stages = append(stages, p.Stage)
+		},
+		WithPairingPollInterval(time.Millisecond),
+		WithPairingKeyStore(keyStore),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &Credentials{BridgeID: "b1", Username: "app-key", ClientKey: "client-key"}
+	if diff := cmp.Diff(expected, credentials); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]PairingStage{PairingWaiting, PairingWaiting, PairingComplete}, stages); diff != "" {
+		t.Errorf("stages mismatch (-want +got):\n%s", diff)
+	}
+	if key, err := keyStore.Get(ClientKeyStoreKey("b1")); err != nil || key != "client-key" {
+		t.Errorf("stored client key = %v, %v, want client-key", key, err)
+	}
+}
+
+func TestPairBridge_Errors(t *testing.T) {
+	t.Run("stops on permanent errors", func(t *testing.T) {
+		server := registrationServer(`[{"error":{"type":7,"address":"/devicetype","description":"invalid value"}}]`)
+		defer server.Close()
+		_, err := PairBridge(context.Background(), Bridge{InternalIPAddress: server.URL}, "app", "test", nil)
+		var registrationErr *client2.RegistrationError
+		if !errors.As(err, &registrationErr) || registrationErr.Type != client2.ErrorTypeInvalidValue {
+			t.Errorf("PairBridge() error = %v, want invalid value registration error", err)
+		}
+	})
+
+	t.Run("handles empty responses", func(t *testing.T) {
+		server := registrationServer(`[]`)
+		defer server.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := PairBridge(ctx, Bridge{InternalIPAddress: server.URL}, "app", "test", nil, WithPairingPollInterval(time.Millisecond))
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("PairBridge() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("gives up when the context expires", func(t *testing.T) {
+		server := registrationServer(linkButtonNotPressed)
+		defer server.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := PairBridge(ctx, Bridge{InternalIPAddress: server.URL}, "app", "test", nil, WithPairingPollInterval(time.Millisecond))
+		if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, client2.LinkButtonNotPressedError) {
+			t.Errorf("PairBridge() error = %v, want deadline exceeded and link button not pressed", err)
+		}
+	})
+}
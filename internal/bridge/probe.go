@@ -0,0 +1,101 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	client2 "github.com/richseviora/huego/internal/client"
+	"github.com/richseviora/huego/internal/client/handlers"
+	"github.com/richseviora/huego/pkg/logger"
+	bridge2 "github.com/richseviora/huego/pkg/resources/bridge"
+	"strings"
+	"time"
+)
+
+const probeTimeout = time.Second * 5
+
+// ProbeConfig reads the unauthenticated /api/0/config of the device at address, which needs no application key, so
+// the bridge ID can be checked before credentials are sent.
+func ProbeConfig(ctx context.Context, address string, l logger.Logger) (*bridge2.Config, error) {
+	return probeConfig(ctx, address, l, probeTimeout)
+}
+
+func probeConfig(ctx context.Context, address string, l logger.Logger, timeout time.Duration) (*bridge2.Config, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	c := client2.NewBridgeRegistrationClient(baseURL(address), l)
+	config, err := handlers.Get[bridge2.Config](ctx, "/api/0/config", c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe %s: %w", address, err)
+	}
+	if config.BridgeID == "" {
+		return nil, fmt.Errorf("device at %s did not report a bridge ID", address)
+	}
+	return config, nil
+}
+
+// baseURL prefixes addresses without a scheme with https, as the bridge only serves the API over TLS. Bare IPv6
+// addresses are bracketed.
+func baseURL(address string) string {
+	if strings.HasPrefix(address, "https://") || strings.HasPrefix(address, "http://") {
+		return address
+	}
+	if isIPv6(address) {
+		address = "[" + address + "]"
+	}
+	return "https://" + address
+}
+
+// resolveBridge confirms that the cached bridge is still at its address. If another device answers there, or nothing
+// does, the bridge is rediscovered and each candidate is probed for a matching ID. changed is true when the returned
+// bridge has a different address than the cached one.
+func (b *Builder) resolveBridge(ctx context.Context, cached Bridge) (result Bridge, changed bool, err error) {
+	var config *bridge2.Config
+	err = errors.New("no cached address")
+	if cached.InternalIPAddress != "" {
+		config, err = ProbeConfig(ctx, cached.Address(), b.Logger)
+	}
+	if err == nil && config.SameBridge(cached.ID) {
+		return cached, false, nil
+	}
+	fields := map[string]interface{}{
+		"bridgeID": cached.ID,
+		"bridgeIP": cached.InternalIPAddress,
+	}
+	if err != nil {
+		fields["error"] = err
+	} else {
+		fields["foundBridgeID"] = config.BridgeID
+	}
+	b.Logger.Warn("Cached bridge address did not match, rediscovering", fields)
+
+	strategies := b.Strategies
+	if strategies == nil {
+		strategies = DefaultStrategies(b.Logger)
+	}
+	discoverCtx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+	defer cancel()
+	candidates, err := Discover(discoverCtx, b.Logger, strategies...)
+	if err != nil {
+		return Bridge{}, false, err
+	}
+	for _, candidate := range candidates {
+		if candidate.Address() == cached.Address() {
+			continue
+		}
+		config, err := ProbeConfig(ctx, candidate.Address(), b.Logger)
+		if err != nil || !config.SameBridge(cached.ID) {
+			continue
+		}
+		result = cached
+		result.InternalIPAddress = candidate.InternalIPAddress
+		result.Port = candidate.Port
+		result.Addresses = candidate.Addresses
+		b.Logger.Info("Found bridge at new address", map[string]interface{}{
+			"bridgeID": cached.ID,
+			"bridgeIP": result.InternalIPAddress,
+		})
+		return result, true, nil
+	}
+	return Bridge{}, false, fmt.Errorf("bridge %s: %w", cached.ID, NoBridgeFoundError)
+}
@@ -0,0 +1,58 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/richseviora/huego/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func configServer(bridgeID string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/0/config" || r.Header.Get("hue-application-key") != "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"Hue Bridge","apiversion":"1.62.0","bridgeid":"` + bridgeID + `"}`))
+	}))
+}
+
+func TestBuilder_resolveBridge(t *testing.T) {
+	other := configServer("ECB5FAFFFE111111")
+	defer other.Close()
+	moved := configServer("ECB5FAFFFE222222")
+	defer moved.Close()
+
+	b := &Builder{
+		Logger:     logger.NoopLogger{},
+		Strategies: []Strategy{&ManualStrategy{Addresses: []string{other.URL, moved.URL}}},
+	}
+
+	t.Run("keeps a matching address", func(t *testing.T) {
+		cached := Bridge{ID: "ecb5fafffe111111", InternalIPAddress: other.URL}
+		result, changed, err := b.resolveBridge(context.Background(), cached)
+		if err != nil || changed || !cmp.Equal(result, cached) {
+			t.Errorf("resolveBridge() = %v, %v, %v, want %v, false, nil", result, changed, err, cached)
+		}
+	})
+
+	t.Run("rediscovers when another bridge answers", func(t *testing.T) {
+		cached := Bridge{ID: "ecb5fafffe222222", InternalIPAddress: other.URL}
+		result, changed, err := b.resolveBridge(context.Background(), cached)
+		expected := Bridge{ID: "ecb5fafffe222222", InternalIPAddress: moved.URL, Addresses: []string{moved.URL}}
+		if err != nil || !changed || !cmp.Equal(result, expected) {
+			t.Errorf("resolveBridge() = %v, %v, %v, want %v, true, nil", result, changed, err, expected)
+		}
+	})
+
+	t.Run("fails when the bridge is not found", func(t *testing.T) {
+		cached := Bridge{ID: "ecb5fafffe333333", InternalIPAddress: other.URL}
+		_, _, err := b.resolveBridge(context.Background(), cached)
+		if !errors.Is(err, NoBridgeFoundError) {
+			t.Errorf("resolveBridge() error = %v, want %v", err, NoBridgeFoundError)
+		}
+	})
+}
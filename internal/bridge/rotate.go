@@ -0,0 +1,78 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	client2 "github.com/richseviora/huego/internal/client"
+	"github.com/richseviora/huego/pkg/logger"
+)
+
+// ErrRevokeFailed is returned with the new credentials when RotateKey replaced the key but could not remove the old
+// one from the bridge. The new key is persisted and working; the old key has to be removed through the Hue app.
+var ErrRevokeFailed = errors.New("failed to revoke previous application key")
+
+// RotateKey replaces oldKey with a newly registered key in one operation: it pairs with the bridge, which needs the
+// link button pressed, persists the new credentials, verifies the bridge accepts them and finally revokes oldKey.
+// persist, if not nil, is called in addition to any key store given with WithPairingKeyStore. If the new key cannot be
+// verified, persist is called again with oldKey and the key store's previous credentials are restored, so callers keep
+// using the key that still works, and the unverified key is revoked using oldKey.
+func RotateKey(ctx context.Context, bridge Bridge, oldKey, appName, instanceName string, persist func(*Credentials) error, progress func(PairingProgress), opts ...PairOption) (*Credentials, error) {
+	options := pairOptions{logger: logger.NoopLogger{}}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	var oldClientKey interface{}
+	if options.keyStore != nil {
+		// PairBridge overwrites the client key as well, so it is kept to restore alongside oldKey.
+		oldClientKey, _ = options.keyStore.Get(ClientKeyStoreKey(bridge.ID))
+	}
+	credentials, err := PairBridge(ctx, bridge, appName, instanceName, progress, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if persist != nil {
+		if err := persist(credentials); err != nil {
+			return credentials, fmt.Errorf("failed to persist new application key: %w", err)
+		}
+	}
+
+	newClient := client2.NewAPIClient(baseURL(bridge.Address()), credentials.Username, options.logger)
+	if _, err := newClient.BridgeService().GetBridge(ctx); err != nil {
+		err = fmt.Errorf("failed to verify new application key: %w", err)
+		if persist != nil {
+			err = errors.Join(err, persist(&Credentials{BridgeID: credentials.BridgeID, Username: oldKey}))
+		}
+		if options.keyStore != nil {
+			err = errors.Join(err, options.keyStore.Set(UsernameStoreKey(credentials.BridgeID), oldKey))
+			if oldClientKey != nil {
+				err = errors.Join(err, options.keyStore.Set(ClientKeyStoreKey(credentials.BridgeID), oldClientKey))
+			} else {
+				err = errors.Join(err, options.keyStore.Delete(ClientKeyStoreKey(credentials.BridgeID)))
+			}
+		}
+		if oldKey != "" && oldKey != credentials.Username {
+			oldClient := client2.NewAPIClient(baseURL(bridge.Address()), oldKey, options.logger)
+			if revokeErr := oldClient.BridgeService().RevokeApplication(ctx, credentials.Username); revokeErr != nil {
+				options.logger.Warn("Failed to revoke unverified application key", map[string]interface{}{
+					"bridgeID": credentials.BridgeID,
+					"error":    revokeErr,
+				})
+				err = errors.Join(err, ErrRevokeFailed, revokeErr)
+			}
+		}
+		return nil, err
+	}
+
+	if oldKey == "" || oldKey == credentials.Username {
+		return credentials, nil
+	}
+	if err := newClient.BridgeService().RevokeApplication(ctx, oldKey); err != nil {
+		options.logger.Warn("Failed to revoke previous application key", map[string]interface{}{
+			"bridgeID": credentials.BridgeID,
+			"error":    err,
+		})
+		return credentials, errors.Join(ErrRevokeFailed, err)
+	}
+	return credentials, nil
+}
@@ -0,0 +1,175 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/richseviora/huego/internal/store"
+	"github.com/richseviora/huego/pkg/logger"
+	"github.com/richseviora/huego/pkg/resources/bridge"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// rotationBridge accepts registrations and serves the bridge resource only to keys in its whitelist.
+type rotationBridge struct {
+	mu        sync.Mutex
+	whitelist map[string]bool
+	revoke    string
+	// rejectNew makes the bridge refuse the registered key, as if it was dropped right after pairing.
+	rejectNew bool
+}
+
+func (f *rotationBridge) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/0/config", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"name":"Hue Bridge","bridgeid":"ECB5FAFFFE222222"}`))
+	})
+	mux.HandleFunc("POST /api", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.whitelist["new-key"] = true
+		_, _ = w.Write([]byte(`[{"success":{"username":"new-key","clientkey":"client-key"}}]`))
+	})
+	mux.HandleFunc("GET /clip/v2/resource/bridge", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		key := r.Header.Get("hue-application-key")
+		if !f.whitelist[key] || (f.rejectNew && key == "new-key") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"errors":[],"data":[{"id":"bridge","bridge_id":"b1","type":"bridge"}]}`))
+	})
+	mux.HandleFunc("DELETE /api/{key}/config/whitelist/{target}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if f.revoke != "" {
+			_, _ = w.Write([]byte(f.revoke))
+			return
+		}
+		delete(f.whitelist, r.PathValue("target"))
+		_, _ = w.Write([]byte(`[{"success":"/config/whitelist/` + r.PathValue("target") + ` deleted"}]`))
+	})
+	return mux
+}
+
+func TestRotateKey(t *testing.T) {
+	fake := &rotationBridge{whitelist: map[string]bool{"old-key": true}}
+	server := httptest.NewServer(fake.handler())
+	defer server.Close()
+	keys := store.NewMemoryKeyStore()
+
+	var persisted []string
+	persist := func(c *Credentials) error {
+		persisted = append(persisted, c.Username)
+		return nil
+	}
+	credentials, err := RotateKey(context.Background(), Bridge{ID: "b1", InternalIPAddress: server.URL}, "old-key", "huego", "test",
+		persist, nil, WithPairingPollInterval(time.Millisecond), WithPairingKeyStore(keys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&Credentials{BridgeID: "b1", Username: "new-key", ClientKey: "client-key"}, credentials); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"new-key"}, persisted); diff != "" {
+		t.Errorf("persisted mismatch (-want +got):\n%s", diff)
+	}
+	if value, err := keys.Get(UsernameStoreKey("b1")); err != nil || value != "new-key" {
+		t.Errorf("stored key = %v, %v, want new-key", value, err)
+	}
+	if diff := cmp.Diff(map[string]bool{"new-key": true}, fake.whitelist); diff != "" {
+		t.Errorf("whitelist mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRotateKey_VerificationFailed(t *testing.T) {
+	fake := &rotationBridge{whitelist: map[string]bool{"old-key": true}, rejectNew: true}
+	server := httptest.NewServer(fake.handler())
+	defer server.Close()
+
+	for _, tt := range []struct {
+		name         string
+		oldClientKey interface{}
+	}{
+		{name: "previous client key", oldClientKey: "old-client-key"},
+		{name: "no previous client key"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := store.NewMemoryKeyStore()
+			_ = keys.Set(UsernameStoreKey("b1"), "old-key")
+			if tt.oldClientKey != nil {
+				_ = keys.Set(ClientKeyStoreKey("b1"), tt.oldClientKey)
+			}
+			credentials, err := RotateKey(context.Background(), Bridge{ID: "b1", InternalIPAddress: server.URL}, "old-key", "huego", "test",
+				nil, nil, WithPairingPollInterval(time.Millisecond), WithPairingKeyStore(keys))
+			if err == nil || credentials != nil {
+				t.Fatalf("RotateKey() = %v, %v, want an error", credentials, err)
+			}
+			if value, err := keys.Get(UsernameStoreKey("b1")); err != nil || value != "old-key" {
+				t.Errorf("stored key = %v, %v, want old-key", value, err)
+			}
+			if value, _ := keys.Get(ClientKeyStoreKey("b1")); value != tt.oldClientKey {
+				t.Errorf("stored client key = %v, want %v", value, tt.oldClientKey)
+			}
+			if diff := cmp.Diff(map[string]bool{"old-key": true}, fake.whitelist); diff != "" {
+				t.Errorf("whitelist mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRotateKey_RevokeRejected(t *testing.T) {
+	fake := &rotationBridge{
+		whitelist: map[string]bool{"old-key": true},
+		revoke:    `[{"error":{"type":4,"address":"/config/whitelist/old-key","description":"method, DELETE, not available for resource"}}]`,
+	}
+	server := httptest.NewServer(fake.handler())
+	defer server.Close()
+
+	credentials, err := RotateKey(context.Background(), Bridge{ID: "b1", InternalIPAddress: server.URL}, "old-key", "huego", "test", nil, nil)
+	var apiErr *bridge.APIError
+	if !errors.Is(err, ErrRevokeFailed) || !errors.As(err, &apiErr) || apiErr.Type != 4 {
+		t.Errorf("RotateKey() error = %v, want %v with API error type 4", err, ErrRevokeFailed)
+	}
+	if credentials == nil || credentials.Username != "new-key" {
+		t.Errorf("RotateKey() credentials = %v, want the new key", credentials)
+	}
+}
+
+func TestBuilder_RotateKeyAfterAddressChange(t *testing.T) {
+	other := configServer("ECB5FAFFFE111111")
+	defer other.Close()
+	fake := &rotationBridge{whitelist: map[string]bool{"old-key": true}}
+	moved := httptest.NewServer(fake.handler())
+	defer moved.Close()
+
+	b := &Builder{
+		FileLocation: filepath.Join(t.TempDir(), "cache.json"),
+		Logger:       logger.NoopLogger{},
+		Strategies:   []Strategy{&ManualStrategy{Addresses: []string{moved.URL}}},
+	}
+	b.BridgeManager = NewCacheManager(b.FileLocation, b.Logger)
+	if err := b.BridgeManager.SaveBridge(Bridge{ID: "ecb5fafffe222222", InternalIPAddress: other.URL}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.BridgeManager.SaveBridgeKeyForID("old-key", "ecb5fafffe222222"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := b.RotateKey(context.Background(), "ecb5fafffe222222", nil); err != nil {
+		t.Fatal(err)
+	}
+	bridge, key, err := b.BridgeManager.GetBridgeAndKey("ecb5fafffe222222")
+	if err != nil || key != "new-key" || bridge.InternalIPAddress != moved.URL {
+		t.Errorf("GetBridgeAndKey() = %v, %s, %v, want bridge at %s with new-key", bridge, key, err, moved.URL)
+	}
+	if diff := cmp.Diff(map[string]bool{"new-key": true}, fake.whitelist); diff != "" {
+		t.Errorf("whitelist mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,297 @@
+package bridge
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/grandcat/zeroconf"
+	"github.com/richseviora/huego/pkg/logger"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultCloudDiscoveryURL is the Hue N-UPnP endpoint listing the bridges registered from the caller's public IP.
+const DefaultCloudDiscoveryURL = "https://discovery.meethue.com"
+
+// DefaultMDNSBrowseTime is how long MDNSStrategy listens for answers.
+const DefaultMDNSBrowseTime = time.Second * 3
+
+// DefaultSSDPWaitTime is how long SSDPStrategy listens for answers.
+const DefaultSSDPWaitTime = time.Second * 3
+
+// MDNSStrategy browses for the _hue._tcp service, collecting IPv4 and IPv6 addresses.
+type MDNSStrategy struct {
+	Logger logger.Logger
+	// BrowseTime overrides DefaultMDNSBrowseTime.
+	BrowseTime time.Duration
+}
+
+func (s *MDNSStrategy) Name() string {
+	return "mdns"
+}
+
+func (s *MDNSStrategy) Discover(ctx context.Context, found chan<- Bridge) error {
+	resolver, err := zeroconf.NewResolver(nil)
+	if err != nil {
+		return fmt.Errorf("failed to initialize resolver: %v", err)
+	}
+	browseTime := s.BrowseTime
+	if browseTime == 0 {
+		browseTime = DefaultMDNSBrowseTime
+	}
+	ctx, cancel := context.WithTimeout(ctx, browseTime)
+	defer cancel()
+
+	entries := make(chan *zeroconf.ServiceEntry)
+	if err := resolver.Browse(ctx, "_hue._tcp", "local.", entries); err != nil {
+		return fmt.Errorf("failed to browse for bridges: %w", err)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case entry, ok := <-entries:
+			if !ok {
+				return nil
+			}
+			b := Bridge{ID: mdnsBridgeID(entry), Port: entry.Port}
+			for _, ip := range entry.AddrIPv4 {
+				b.Addresses = append(b.Addresses, ip.String())
+			}
+			for _, ip := range entry.AddrIPv6 {
+				b.Addresses = append(b.Addresses, ip.String())
+			}
+			if len(b.Addresses) == 0 {
+				continue
+			}
+			b.InternalIPAddress = b.Addresses[0]
+			if !send(ctx, found, b) {
+				return nil
+			}
+		}
+	}
+}
+
+// mdnsBridgeID returns the bridge ID from the TXT record, matching the ID used by cloud discovery and
+// /api/0/config. The instance name, such as "Hue Bridge - 1A2B3C", is only used when the record is missing.
+func mdnsBridgeID(entry *zeroconf.ServiceEntry) string {
+	for _, txt := range entry.Text {
+		if id, ok := strings.CutPrefix(txt, "bridgeid="); ok && id != "" {
+			return strings.ToLower(id)
+		}
+	}
+	return entry.Instance
+}
+
+// CloudStrategy asks the N-UPnP endpoint. The endpoint is rate limited, so prefer running it as a fallback.
+type CloudStrategy struct {
+	// URL overrides DefaultCloudDiscoveryURL, for example to point at a local stand-in in tests.
+	URL        string
+	HTTPClient *http.Client
+}
+
+func (s *CloudStrategy) Name() string {
+	return "cloud"
+}
+
+func (s *CloudStrategy) Discover(ctx context.Context, found chan<- Bridge) error {
+	endpoint := s.URL
+	if endpoint == "" {
+		endpoint = DefaultCloudDiscoveryURL
+	}
+	httpClient := s.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("cloud discovery failed with status: %s", resp.Status)
+	}
+	var bridges []Bridge
+	if err := json.NewDecoder(resp.Body).Decode(&bridges); err != nil {
+		return err
+	}
+	for _, b := range bridges {
+		if !send(ctx, found, b) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// SSDPStrategy sends a UPnP M-SEARCH and keeps the answers carrying a hue-bridgeid header.
+type SSDPStrategy struct {
+	// WaitTime overrides DefaultSSDPWaitTime.
+	WaitTime time.Duration
+}
+
+func (s *SSDPStrategy) Name() string {
+	return "ssdp"
+}
+
+const ssdpSearch = "M-SEARCH * HTTP/1.1\r\n" +
+	"HOST: 239.255.255.250:1900\r\n" +
+	"MAN: \"ssdp:discover\"\r\n" +
+	"MX: 2\r\n" +
+	"ST: ssdp:all\r\n\r\n"
+
+func (s *SSDPStrategy) Discover(ctx context.Context, found chan<- Bridge) error {
+	waitTime := s.WaitTime
+	if waitTime == 0 {
+		waitTime = DefaultSSDPWaitTime
+	}
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	target := &net.UDPAddr{IP: net.IPv4(239, 255, 255, 250), Port: 1900}
+	if _, err := conn.WriteTo([]byte(ssdpSearch), target); err != nil {
+		return err
+	}
+	deadline := time.Now().Add(waitTime)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return err
+	}
+	buf := make([]byte, 2048)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				return nil
+			}
+			return err
+		}
+		b, ok := parseSSDPResponse(buf[:n], addr)
+		if !ok {
+			continue
+		}
+		if !send(ctx, found, b) {
+			return nil
+		}
+	}
+}
+
+// parseSSDPResponse reads a bridge from an M-SEARCH answer. Other UPnP devices answer too and are skipped.
+func parseSSDPResponse(data []byte, from net.Addr) (Bridge, bool) {
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), nil)
+	if err != nil {
+		return Bridge{}, false
+	}
+	_ = resp.Body.Close()
+	id := resp.Header.Get("hue-bridgeid")
+	if id == "" {
+		return Bridge{}, false
+	}
+	host := ""
+	if location, err := url.Parse(resp.Header.Get("Location")); err == nil {
+		host = location.Hostname()
+	}
+	if host == "" {
+		if udp, ok := from.(*net.UDPAddr); ok {
+			host = udp.IP.String()
+		}
+	}
+	if host == "" {
+		return Bridge{}, false
+	}
+	return Bridge{ID: id, InternalIPAddress: host}, true
+}
+
+// ManualStrategy probes a fixed list of addresses, such as ones entered by the user, and reports those that answer
+// as a bridge.
+type ManualStrategy struct {
+	Addresses []string
+	Logger    logger.Logger
+}
+
+func (s *ManualStrategy) Name() string {
+	return "manual"
+}
+
+func (s *ManualStrategy) Discover(ctx context.Context, found chan<- Bridge) error {
+	l := s.Logger
+	if l == nil {
+		l = logger.NoopLogger{}
+	}
+	for _, address := range s.Addresses {
+		config, err := ProbeConfig(ctx, address, l)
+		if err != nil {
+			l.Debug("Address did not answer as a bridge", map[string]interface{}{
+				"address": address,
+				"error":   err,
+			})
+			continue
+		}
+		if !send(ctx, found, Bridge{ID: config.BridgeID, InternalIPAddress: address}) {
+			return nil
+		}
+	}
+	return nil
+}
+
+type fallback struct {
+	strategies []Strategy
+}
+
+// Fallback runs the strategies one after the other and stops after the first that finds a bridge.
+func Fallback(strategies ...Strategy) Strategy {
+	return &fallback{strategies: strategies}
+}
+
+func (f *fallback) Name() string {
+	names := make([]string, 0, len(f.strategies))
+	for _, s := range f.strategies {
+		names = append(names, s.Name())
+	}
+	return "fallback(" + strings.Join(names, ", ") + ")"
+}
+
+func (f *fallback) Discover(ctx context.Context, found chan<- Bridge) error {
+	var lastErr error
+	for _, s := range f.strategies {
+		inner := make(chan Bridge)
+		done := make(chan error, 1)
+		go func() {
+			done <- s.Discover(ctx, inner)
+			close(inner)
+		}()
+		foundAny := false
+		for b := range inner {
+			foundAny = true
+			send(ctx, found, b)
+		}
+		if err := <-done; err != nil {
+			lastErr = err
+		}
+		if foundAny || ctx.Err() != nil {
+			return nil
+		}
+	}
+	return lastErr
+}
+
+func send(ctx context.Context, found chan<- Bridge, b Bridge) bool {
+	select {
+	case found <- b:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,165 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"github.com/richseviora/huego/pkg/logger"
+	"net"
+	"net/netip"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultScanConcurrency is how many hosts SubnetScanStrategy probes at once.
+	DefaultScanConcurrency = 32
+	// DefaultScanProbeTimeout is how long SubnetScanStrategy waits for each host.
+	DefaultScanProbeTimeout = time.Second
+	// maxInterfacePrefix narrows interface subnets larger than a /24 to the /24 around the interface address, so a
+	// /16 office network is not scanned host by host.
+	maxInterfacePrefix = 24
+)
+
+// SubnetScanStrategy probes every host in a range for /api/0/config, for networks where multicast is blocked and the
+// cloud endpoint is unreachable. Only hosts answering with a bridge ID are reported.
+type SubnetScanStrategy struct {
+	// CIDRs are the IPv4 ranges to scan, such as "192.168.1.0/24". Defaults to the subnets of the local interfaces.
+	CIDRs []string
+	// Concurrency overrides DefaultScanConcurrency.
+	Concurrency int
+	// ProbeTimeout overrides DefaultScanProbeTimeout.
+	ProbeTimeout time.Duration
+	// Scheme is "https" by default. Port is added to each host when it is not the scheme's default.
+	Scheme string
+	Port   int
+	Logger logger.Logger
+}
+
+func (s *SubnetScanStrategy) Name() string {
+	return "subnet"
+}
+
+func (s *SubnetScanStrategy) Discover(ctx context.Context, found chan<- Bridge) error {
+	l := s.Logger
+	if l == nil {
+		l = logger.NoopLogger{}
+	}
+	prefixes, err := s.prefixes()
+	if err != nil {
+		return err
+	}
+	concurrency := s.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultScanConcurrency
+	}
+	timeout := s.ProbeTimeout
+	if timeout == 0 {
+		timeout = DefaultScanProbeTimeout
+	}
+	scheme := s.Scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+
+	hosts := make(chan netip.Addr)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for host := range hosts {
+				address := host.String()
+				if s.Port != 0 {
+					address = net.JoinHostPort(address, strconv.Itoa(s.Port))
+				}
+				config, err := probeConfig(ctx, scheme+"://"+address, l, timeout)
+				if err != nil || !IsFullBridgeID(config.BridgeID) {
+					continue
+				}
+				send(ctx, found, Bridge{ID: config.BridgeID, InternalIPAddress: host.String(), Port: s.Port})
+			}
+		}()
+	}
+	defer wg.Wait()
+	defer close(hosts)
+	for _, prefix := range prefixes {
+		for host := range hostsIn(prefix) {
+			select {
+			case hosts <- host:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func (s *SubnetScanStrategy) prefixes() ([]netip.Prefix, error) {
+	if len(s.CIDRs) == 0 {
+		return InterfacePrefixes()
+	}
+	result := make([]netip.Prefix, 0, len(s.CIDRs))
+	for _, cidr := range s.CIDRs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+		}
+		if !prefix.Addr().Is4() {
+			return nil, fmt.Errorf("invalid CIDR %q: only IPv4 ranges can be scanned", cidr)
+		}
+		result = append(result, prefix.Masked())
+	}
+	return result, nil
+}
+
+// InterfacePrefixes returns the IPv4 subnets of the local interfaces that are up, excluding loopback.
+func InterfacePrefixes() ([]netip.Prefix, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var result []netip.Prefix
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addresses, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, a := range addresses {
+			ipNet, ok := a.(*net.IPNet)
+			if !ok || ipNet.IP.To4() == nil {
+				continue
+			}
+			addr, _ := netip.AddrFromSlice(ipNet.IP.To4())
+			bits, _ := ipNet.Mask.Size()
+			if bits < maxInterfacePrefix {
+				bits = maxInterfacePrefix
+			}
+			result = append(result, netip.PrefixFrom(addr, bits).Masked())
+		}
+	}
+	return result, nil
+}
+
+// hostsIn yields every address of the prefix, leaving out the network and broadcast addresses of ranges that have
+// them.
+func hostsIn(prefix netip.Prefix) func(yield func(netip.Addr) bool) {
+	return func(yield func(netip.Addr) bool) {
+		first := prefix.Addr()
+		hasBroadcast := prefix.Bits() < 31
+		if hasBroadcast {
+			first = first.Next()
+		}
+		for addr := first; addr.IsValid() && prefix.Contains(addr); addr = addr.Next() {
+			if hasBroadcast && !prefix.Contains(addr.Next()) {
+				return
+			}
+			if !yield(addr) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package bridge
+
+import (
+	"context"
+	"github.com/google/go-cmp/cmp"
+	"github.com/richseviora/huego/pkg/logger"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+func TestSubnetScanStrategy(t *testing.T) {
+	server := configServer("ECB5FAFFFE111111")
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(u.Port())
+
+	strategy := &SubnetScanStrategy{CIDRs: []string{"127.0.0.0/30"}, Scheme: "http", Port: port, Concurrency: 2}
+	result, err := Discover(context.Background(), logger.NoopLogger{}, strategy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Bridge{{ID: "ecb5fafffe111111", InternalIPAddress: "127.0.0.1", Port: port, Addresses: []string{"127.0.0.1"}}}
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSubnetScanStrategy_InvalidCIDR(t *testing.T) {
+	for _, cidr := range []string{"192.168.1.0", "fd00::/120"} {
+		strategy := &SubnetScanStrategy{CIDRs: []string{cidr}}
+		if err := strategy.Discover(context.Background(), make(chan Bridge)); err == nil {
+			t.Errorf("Discover(%s) error = nil, want error", cidr)
+		}
+	}
+}
+
+func TestHostsIn(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		expected []string
+	}{
+		{"192.168.1.0/30", []string{"192.168.1.1", "192.168.1.2"}},
+		{"192.168.1.4/31", []string{"192.168.1.4", "192.168.1.5"}},
+		{"192.168.1.9/32", []string{"192.168.1.9"}},
+	}
+	for _, tt := range tests {
+		var result []string
+		for addr := range hostsIn(netip.MustParsePrefix(tt.prefix)) {
+			result = append(result, addr.String())
+		}
+		if diff := cmp.Diff(tt.expected, result); diff != "" {
+			t.Errorf("hostsIn(%s) mismatch (-want +got):\n%s", tt.prefix, diff)
+		}
+	}
+}
@@ -1,7 +1,28 @@
 package bridge
 
+import (
+	"net"
+	"strconv"
+)
+
 type Bridge struct {
 	ID                string `json:"id"`
 	InternalIPAddress string `json:"internalipaddress"`
 	Port              int    `json:"port"`
+	// Addresses holds every address the bridge was found at, including IPv6. InternalIPAddress is the preferred one,
+	// IPv4 when the bridge has one.
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+// Address returns the host to use in URLs: IPv6 addresses are bracketed, and the port is added unless it is the
+// default HTTPS port.
+func (b Bridge) Address() string {
+	host := b.InternalIPAddress
+	if b.Port != 0 && b.Port != 443 {
+		return net.JoinHostPort(host, strconv.Itoa(b.Port))
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+		return "[" + host + "]"
+	}
+	return host
 }
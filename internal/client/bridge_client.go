@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"errors"
+	"fmt"
 	"github.com/richseviora/huego/internal/client/handlers"
 	"github.com/richseviora/huego/pkg/logger"
 	"github.com/richseviora/huego/pkg/resources"
@@ -56,30 +57,48 @@ var (
 	_ common.RequestProcessor = &BridgeRegistrationClient{}
 )
 
+// RegisterDevice makes a single registration attempt and returns the application key. It returns an error matching
+// LinkButtonNotPressedError until the link button on the bridge is pressed.
 func (c *BridgeRegistrationClient) RegisterDevice(ctx context.Context, appName, instanceName string) (string, error) {
+	result, err := c.Register(ctx, appName, instanceName)
+	if err != nil {
+		return "", err
+	}
+	return result.Username, nil
+}
+
+// Register makes a single registration attempt and returns both the application key and the client key used for
+// entertainment streaming. Errors reported by the bridge are returned as *RegistrationError.
+func (c *BridgeRegistrationClient) Register(ctx context.Context, appName, instanceName string) (*resources.BridgeRegistrationSuccess, error) {
 	if appName == "" {
 		appName = "huego"
 	}
-
 	if instanceName == "" {
 		var err error
 		instanceName, err = generateRandomString(6)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 	}
+	if len(appName) > MaxAppNameLength || len(instanceName) > MaxInstanceNameLength {
+		return nil, fmt.Errorf("application name must be at most %d and instance name at most %d characters", MaxAppNameLength, MaxInstanceNameLength)
+	}
 	response, err := c.registerDevice(ctx, appName, instanceName)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	registrationError := (*response)[0].Error
-	if registrationError != nil {
-		if registrationError.Description == "link button not pressed" {
-			return "", LinkButtonNotPressedError
+	if response == nil || len(*response) == 0 {
+		return nil, fmt.Errorf("empty registration response: %w", client2.ErrBadResponse)
+	}
+	for _, r := range *response {
+		if r.Error != nil {
+			return nil, &RegistrationError{Type: r.Error.Type, Address: r.Error.Address, Description: r.Error.Description}
+		}
+		if r.Success != nil && r.Success.Username != "" {
+			return r.Success, nil
 		}
 	}
-	registration := (*response)[0].Success
-	return registration.Username, nil
+	return nil, fmt.Errorf("registration response had no username: %w", client2.ErrBadResponse)
 }
 
 // registerDevice sends a registration request to the Hue bridge
@@ -91,8 +110,48 @@ func (c *BridgeRegistrationClient) registerDevice(ctx context.Context, appName,
 	return handlers.Post[resources.BridgeRegistrationResponseBody](ctx, "/api", request, c)
 }
 
+// The bridge limits the two halves of the devicetype.
+const (
+	MaxAppNameLength      = 20
+	MaxInstanceNameLength = 19
+)
+
 var LinkButtonNotPressedError = errors.New("link button not pressed")
 
+// Error types the bridge reports for registration requests.
+const (
+	ErrorTypeUnauthorized          = 1
+	ErrorTypeInvalidJSON           = 2
+	ErrorTypeResourceNotAvailable  = 3
+	ErrorTypeMethodNotAvailable    = 4
+	ErrorTypeMissingParameters     = 5
+	ErrorTypeParameterNotAvailable = 6
+	ErrorTypeInvalidValue          = 7
+	ErrorTypeLinkButtonNotPressed  = 101
+	ErrorTypeInternal              = 901
+)
+
+// RegistrationError is an error the bridge returned for a registration request.
+type RegistrationError struct {
+	Type        int
+	Address     string
+	Description string
+}
+
+func (e *RegistrationError) Error() string {
+	return fmt.Sprintf("registration failed: %s (type %d)", e.Description, e.Type)
+}
+
+// Is matches LinkButtonNotPressedError for the link button error type.
+func (e *RegistrationError) Is(target error) bool {
+	return target == LinkButtonNotPressedError && e.Type == ErrorTypeLinkButtonNotPressed
+}
+
+// Temporary reports whether retrying the same request may succeed.
+func (e *RegistrationError) Temporary() bool {
+	return e.Type == ErrorTypeLinkButtonNotPressed || e.Type == ErrorTypeInternal
+}
+
 func generateRandomString(length int) (string, error) {
 	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	b := make([]byte, length)
@@ -3,14 +3,37 @@ package client
 import (
 	"context"
 	"crypto/tls"
-	"errors"
 	behavior_instance2 "github.com/richseviora/huego/internal/services/behavior_instance"
 	behavior_script2 "github.com/richseviora/huego/internal/services/behavior_script"
+	bridge2 "github.com/richseviora/huego/internal/services/bridge"
+	button2 "github.com/richseviora/huego/internal/services/button"
+	contact2 "github.com/richseviora/huego/internal/services/contact"
+	device_power2 "github.com/richseviora/huego/internal/services/device_power"
+	device_software_update2 "github.com/richseviora/huego/internal/services/device_software_update"
+	event2 "github.com/richseviora/huego/internal/services/event"
+	light_level2 "github.com/richseviora/huego/internal/services/light_level"
 	motion2 "github.com/richseviora/huego/internal/services/motion"
+	relative_rotary2 "github.com/richseviora/huego/internal/services/relative_rotary"
+	smart_scene2 "github.com/richseviora/huego/internal/services/smart_scene"
+	tamper2 "github.com/richseviora/huego/internal/services/tamper"
+	temperature2 "github.com/richseviora/huego/internal/services/temperature"
+	zigbee_device_discovery2 "github.com/richseviora/huego/internal/services/zigbee_device_discovery"
 	"github.com/richseviora/huego/pkg/logger"
 	"github.com/richseviora/huego/pkg/resources/behavior_instance"
 	"github.com/richseviora/huego/pkg/resources/behavior_script"
+	"github.com/richseviora/huego/pkg/resources/bridge"
+	"github.com/richseviora/huego/pkg/resources/button"
+	"github.com/richseviora/huego/pkg/resources/contact"
+	"github.com/richseviora/huego/pkg/resources/device_power"
+	"github.com/richseviora/huego/pkg/resources/device_software_update"
+	"github.com/richseviora/huego/pkg/resources/event"
+	"github.com/richseviora/huego/pkg/resources/light_level"
 	"github.com/richseviora/huego/pkg/resources/motion"
+	"github.com/richseviora/huego/pkg/resources/relative_rotary"
+	"github.com/richseviora/huego/pkg/resources/smart_scene"
+	"github.com/richseviora/huego/pkg/resources/tamper"
+	"github.com/richseviora/huego/pkg/resources/temperature"
+	"github.com/richseviora/huego/pkg/resources/zigbee_device_discovery"
 	"net/http"
 	"strings"
 	"time"
@@ -47,23 +70,36 @@ const (
 
 // APIClient handles API communication
 type APIClient struct {
-	logger                    logger.Logger
-	baseURL                   string
-	applicationKey            string
-	httpClient                *http.Client
-	timeout                   time.Duration
-	keyStore                  store.KeyStore
-	initMode                  InitMode
-	limiter                   *rate.Limiter
-	lightService              light2.LightService
-	sceneService              scene2.SceneService
-	roomService               room2.RoomService
-	zoneService               zone2.ZoneService
-	deviceService             device.Service
-	zigbeeConnectivityService zigbee_connectivity.Service
-	motionService             motion.Service
-	behaviorInstanceService   behavior_instance.Service
-	behaviorScriptService     behavior_script.Service
+	logger                       logger.Logger
+	baseURL                      string
+	applicationKey               string
+	httpClient                   *http.Client
+	streamClient                 *http.Client
+	timeout                      time.Duration
+	keyStore                     store.KeyStore
+	initMode                     InitMode
+	limiter                      *rate.Limiter
+	lightService                 light2.LightService
+	sceneService                 scene2.SceneService
+	roomService                  room2.RoomService
+	zoneService                  zone2.ZoneService
+	deviceService                device.Service
+	zigbeeConnectivityService    zigbee_connectivity.Service
+	motionService                motion.Service
+	behaviorInstanceService      behavior_instance.Service
+	behaviorScriptService        behavior_script.Service
+	smartSceneService            smart_scene.Service
+	bridgeService                bridge.Service
+	zigbeeDeviceDiscoveryService zigbee_device_discovery.Service
+	tamperService                tamper.Service
+	contactService               contact.Service
+	eventService                 event.Service
+	relativeRotaryService        relative_rotary.Service
+	buttonService                button.Service
+	temperatureService           temperature.Service
+	lightLevelService            light_level.Service
+	deviceSoftwareUpdateService  device_software_update.Service
+	devicePowerService           device_power.Service
 }
 
 func (c *APIClient) Logger() logger.Logger {
@@ -78,6 +114,54 @@ func (c *APIClient) BehaviorScriptService() behavior_script.Service {
 	return c.behaviorScriptService
 }
 
+func (c *APIClient) DevicePowerService() device_power.Service {
+	return c.devicePowerService
+}
+
+func (c *APIClient) DeviceSoftwareUpdateService() device_software_update.Service {
+	return c.deviceSoftwareUpdateService
+}
+
+func (c *APIClient) TemperatureService() temperature.Service {
+	return c.temperatureService
+}
+
+func (c *APIClient) LightLevelService() light_level.Service {
+	return c.lightLevelService
+}
+
+func (c *APIClient) ButtonService() button.Service {
+	return c.buttonService
+}
+
+func (c *APIClient) RelativeRotaryService() relative_rotary.Service {
+	return c.relativeRotaryService
+}
+
+func (c *APIClient) EventService() event.Service {
+	return c.eventService
+}
+
+func (c *APIClient) ContactService() contact.Service {
+	return c.contactService
+}
+
+func (c *APIClient) TamperService() tamper.Service {
+	return c.tamperService
+}
+
+func (c *APIClient) ZigbeeDeviceDiscoveryService() zigbee_device_discovery.Service {
+	return c.zigbeeDeviceDiscoveryService
+}
+
+func (c *APIClient) BridgeService() bridge.Service {
+	return c.bridgeService
+}
+
+func (c *APIClient) SmartSceneService() smart_scene.Service {
+	return c.smartSceneService
+}
+
 func (c *APIClient) MotionService() motion.Service {
 	return c.motionService
 }
@@ -124,19 +208,32 @@ func NewAPIClient(ipAddress string, applicationKey string, logger logger.Logger,
 		logger:         logger,
 		baseURL:        baseUrl,
 		httpClient:     NewHTTPClient(),
+		streamClient:   newStreamClient(),
 		timeout:        30 * time.Second,
 		applicationKey: applicationKey,
 		limiter:        rate.NewLimiter(rate.Every(time.Second/10), 1),
 	}
-	c.sceneService = scene.NewSceneService(c, c.logger)
 	c.lightService = light.NewLightService(c, c.logger)
 	c.roomService = room.NewRoomService(c, c.logger)
 	c.zoneService = zone.NewZoneService(c, c.logger)
 	c.deviceService = device2.NewDeviceManager(c, c.logger)
+	c.sceneService = scene.NewSceneService(c, c.logger, c.roomService, c.zoneService, c.deviceService, c.lightService)
 	c.zigbeeConnectivityService = zigbee_connectivity2.NewManager(c, c.logger)
 	c.motionService = motion2.NewManager(c, c.logger)
 	c.behaviorInstanceService = behavior_instance2.NewManager(c, c.logger)
 	c.behaviorScriptService = behavior_script2.NewManager(c, c.logger)
+	c.smartSceneService = smart_scene2.NewManager(c, c.logger)
+	c.bridgeService = bridge2.NewManager(c, c.applicationKey, c.logger)
+	c.zigbeeDeviceDiscoveryService = zigbee_device_discovery2.NewManager(c, c.logger)
+	c.tamperService = tamper2.NewManager(c, c.logger)
+	c.contactService = contact2.NewManager(c, c.logger)
+	c.eventService = event2.NewManager(c, c.logger)
+	c.relativeRotaryService = relative_rotary2.NewManager(c, c.logger)
+	c.buttonService = button2.NewManager(c, c.logger)
+	c.lightLevelService = light_level2.NewManager(c, c.logger)
+	c.temperatureService = temperature2.NewManager(c, c.logger)
+	c.deviceSoftwareUpdateService = device_software_update2.NewManager(c, c.logger)
+	c.devicePowerService = device_power2.NewManager(c, c.logger)
 
 	for _, opt := range opts {
 		opt(c)
@@ -155,12 +252,28 @@ func NewHTTPClient() *http.Client {
 	}
 }
 
+func newStreamClient() *http.Client {
+	result := NewHTTPClient()
+	result.Timeout = 0
+	return result
+}
+
 func (c *APIClient) BaseURL() string {
 	return c.baseURL
 }
 
 // Do executes an HTTP request and returns the response
 func (c *APIClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	return c.do(ctx, req, c.httpClient)
+}
+
+// DoStream executes a request whose response body stays open, such as the event stream. It uses a client without a
+// timeout so the body is only closed by the bridge or ctx.
+func (c *APIClient) DoStream(ctx context.Context, req *http.Request) (*http.Response, error) {
+	return c.do(ctx, req, c.streamClient)
+}
+
+func (c *APIClient) do(ctx context.Context, req *http.Request, httpClient *http.Client) (*http.Response, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -172,7 +285,7 @@ func (c *APIClient) Do(ctx context.Context, req *http.Request) (*http.Response,
 		return nil, err
 	}
 	req = req.WithContext(ctx)
-	response, err := c.httpClient.Do(req)
+	response, err := httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -188,25 +301,15 @@ func (c *APIClient) Do(ctx context.Context, req *http.Request) (*http.Response,
 	return response, err
 }
 
+// createApplicationKey registers under the "huego" application name with a random instance name, so every key is
+// listed separately in the bridge's whitelist.
 func createApplicationKey(ctx context.Context, c *BridgeRegistrationClient) (string, error) {
-	res, err := c.registerDevice(ctx, "huego", "1234567890")
+	key, err := c.RegisterDevice(ctx, "huego", "")
 	if err != nil {
 		c.Logger().Error("Failed to register device", map[string]interface{}{
 			"error": err,
 		})
 		return "", err
 	}
-	for _, response := range *res {
-		if response.Success != nil {
-			return response.Success.ClientKey, nil
-		}
-		if response.Error != nil {
-			c.Logger().Error("Failed to register device", map[string]interface{}{
-				"error": err,
-			})
-			return "", errors.New("failed to register device")
-		}
-	}
-
-	return "", errors.New("failed to register device")
+	return key, nil
 }
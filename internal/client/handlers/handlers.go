@@ -121,6 +121,30 @@ func Delete(ctx context.Context, path string, c common.RequestProcessor) error {
 	return nil
 }
 
+// DeleteWithResponse performs a DELETE request and unmarshals the response into the provided type, for the v1 API
+// which reports failures in the body.
+func DeleteWithResponse[T any](ctx context.Context, path string, c common.RequestProcessor) (*T, error) {
+	req, err := http.NewRequest(http.MethodDelete, c.BaseURL()+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	return decodeResponse[T](bodyBytes, req.URL.String(), c.Logger())
+}
+
 // Post performs a POST request and unmarshals the response into the provided type
 func Post[T any](ctx context.Context, path string, body interface{}, c common.RequestProcessor) (*T, error) {
 	jsonBody, err := json.Marshal(body)
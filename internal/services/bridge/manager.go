@@ -0,0 +1,95 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"github.com/richseviora/huego/internal/client/handlers"
+	"github.com/richseviora/huego/pkg/logger"
+	"github.com/richseviora/huego/pkg/resources/bridge"
+	"github.com/richseviora/huego/pkg/resources/client"
+	"github.com/richseviora/huego/pkg/resources/common"
+)
+
+const (
+	basePath   = "/clip/v2/resource/bridge"
+	configPath = "/api/0/config"
+)
+
+type Manager struct {
+	client common.RequestProcessor
+	// applicationKey is part of the path of v1 requests, which do not use the hue-application-key header.
+	applicationKey string
+	logger         logger.Logger
+}
+
+var (
+	_ bridge.Service = &Manager{}
+)
+
+func NewManager(client common.RequestProcessor, applicationKey string, logger logger.Logger) *Manager {
+	return &Manager{
+		client:         client,
+		applicationKey: applicationKey,
+		logger:         logger,
+	}
+}
+
+func (m *Manager) GetBridge(ctx context.Context) (*bridge.Data, error) {
+	result, err := handlers.Get[common.ResourceList[bridge.Data]](ctx, basePath, m.client)
+	if err != nil {
+		return nil, err
+	}
+	return handlers.FirstOrError(result)
+}
+
+func (m *Manager) GetConfig(ctx context.Context) (*bridge.Config, error) {
+	return handlers.Get[bridge.Config](ctx, configPath, m.client)
+}
+
+func (m *Manager) CheckCompatibility(ctx context.Context, features ...bridge.Feature) ([]bridge.Incompatibility, error) {
+	config, err := m.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := config.Incompatibilities(features...)
+	for _, i := range result {
+		m.logger.Warn("Bridge firmware is older than a feature requires", map[string]interface{}{
+			"feature":       i.Feature.Name,
+			"minAPIVersion": i.Feature.MinAPIVersion,
+			"apiVersion":    i.APIVersion,
+			"bridgeID":      config.BridgeID,
+		})
+	}
+	return result, nil
+}
+
+func (m *Manager) ListApplications(ctx context.Context) ([]bridge.Application, error) {
+	config, err := handlers.Get[bridge.AuthenticatedConfig](ctx, "/api/"+m.applicationKey+"/config", m.client)
+	if err != nil {
+		return nil, err
+	}
+	// The bridge answers an unknown key with the public configuration, which has no whitelist.
+	if config.Whitelist == nil {
+		return nil, client.ErrUnauthorized
+	}
+	return config.Applications(), nil
+}
+
+func (m *Manager) RevokeApplication(ctx context.Context, key string) error {
+	if key == "" {
+		return fmt.Errorf("application key must not be empty")
+	}
+	result, err := handlers.DeleteWithResponse[[]bridge.APIResponse](ctx, "/api/"+m.applicationKey+"/config/whitelist/"+key, m.client)
+	if err != nil {
+		return err
+	}
+	for _, r := range *result {
+		if r.Error != nil {
+			return r.Error
+		}
+	}
+	m.logger.Info("Revoked application key", map[string]interface{}{
+		"key": key[:min(len(key), 4)] + "...",
+	})
+	return nil
+}
@@ -0,0 +1,45 @@
+package button
+
+import (
+	"context"
+	"github.com/richseviora/huego/internal/client/handlers"
+	common2 "github.com/richseviora/huego/internal/services/common"
+	"github.com/richseviora/huego/pkg/logger"
+	"github.com/richseviora/huego/pkg/resources/button"
+	"github.com/richseviora/huego/pkg/resources/common"
+)
+
+const basePath = "/clip/v2/resource/button"
+
+type Manager struct {
+	client common.RequestProcessor
+	logger logger.Logger
+}
+
+func (m *Manager) CollectionPath() string {
+	return basePath
+}
+
+func (m *Manager) ResourcePath(id string) string {
+	return basePath + "/" + id
+}
+
+func (m *Manager) GetAllButtons(ctx context.Context) (*common.ResourceList[button.Data], error) {
+	return handlers.Get[common.ResourceList[button.Data]](ctx, m.CollectionPath(), m.client)
+}
+
+func (m *Manager) GetButton(ctx context.Context, id string) (*button.Data, error) {
+	return handlers.GetSingularResource[button.Data](id, m.ResourcePath(id), ctx, m.client, "button")
+}
+
+var (
+	_ button.Service           = &Manager{}
+	_ common2.ResourcePathable = &Manager{}
+)
+
+func NewManager(client common.RequestProcessor, logger logger.Logger) *Manager {
+	return &Manager{
+		client: client,
+		logger: logger,
+	}
+}
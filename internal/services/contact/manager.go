@@ -0,0 +1,49 @@
+package contact
+
+import (
+	"context"
+	"github.com/richseviora/huego/internal/client/handlers"
+	common2 "github.com/richseviora/huego/internal/services/common"
+	"github.com/richseviora/huego/pkg/logger"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/contact"
+)
+
+const basePath = "/clip/v2/resource/contact"
+
+type Manager struct {
+	client common.RequestProcessor
+	logger logger.Logger
+}
+
+func (m *Manager) CollectionPath() string {
+	return basePath
+}
+
+func (m *Manager) ResourcePath(id string) string {
+	return basePath + "/" + id
+}
+
+func (m *Manager) GetAllContacts(ctx context.Context) (*common.ResourceList[contact.Data], error) {
+	return handlers.Get[common.ResourceList[contact.Data]](ctx, m.CollectionPath(), m.client)
+}
+
+func (m *Manager) GetContact(ctx context.Context, id string) (*contact.Data, error) {
+	return handlers.GetSingularResource[contact.Data](id, m.ResourcePath(id), ctx, m.client, "contact")
+}
+
+func (m *Manager) UpdateContact(ctx context.Context, id string, update contact.UpdateRequest) (*common.Reference, error) {
+	return handlers.UpdateResource(m.ResourcePath(id), ctx, update, m.client, "contact")
+}
+
+var (
+	_ contact.Service          = &Manager{}
+	_ common2.ResourcePathable = &Manager{}
+)
+
+func NewManager(client common.RequestProcessor, logger logger.Logger) *Manager {
+	return &Manager{
+		client: client,
+		logger: logger,
+	}
+}
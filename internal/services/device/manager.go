@@ -30,5 +30,25 @@ func (m *DeviceManager) GetAllDevices(ctx context.Context) (*common.ResourceList
 }
 
 func (m *DeviceManager) GetDevice(ctx context.Context, id string) (*device.Data, error) {
-	return handlers.GetSingularResource[device.Data](id, "/clip/v2/resource/device/"+id, ctx, m.client, "device")
+	return handlers.GetSingularResource[device.Data](id, devicePath(id), ctx, m.client, "device")
+}
+
+func (m *DeviceManager) UpdateDevice(ctx context.Context, id string, update device.Update) (*common.Reference, error) {
+	return handlers.UpdateResource(devicePath(id), ctx, update, m.client, "device")
+}
+
+func (m *DeviceManager) RenameDevice(ctx context.Context, id string, name string) (*common.Reference, error) {
+	return m.UpdateDevice(ctx, id, device.Update{Metadata: &device.MetadataUpdate{Name: name}})
+}
+
+func (m *DeviceManager) IdentifyDevice(ctx context.Context, id string) (*common.Reference, error) {
+	return m.UpdateDevice(ctx, id, device.Update{Identify: &device.IdentifyUpdate{Action: device.IdentifyAction}})
+}
+
+func (m *DeviceManager) DeleteDevice(ctx context.Context, id string) error {
+	return handlers.Delete(ctx, devicePath(id), m.client)
+}
+
+func devicePath(id string) string {
+	return "/clip/v2/resource/device/" + id
 }
@@ -0,0 +1,35 @@
+package device_power
+
+import (
+	"context"
+	"github.com/richseviora/huego/internal/client/handlers"
+	"github.com/richseviora/huego/pkg/logger"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/device_power"
+)
+
+const basePath = "/clip/v2/resource/device_power"
+
+type Manager struct {
+	client common.RequestProcessor
+	logger logger.Logger
+}
+
+var (
+	_ device_power.Service = &Manager{}
+)
+
+func NewManager(client common.RequestProcessor, logger logger.Logger) *Manager {
+	return &Manager{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (m *Manager) GetAllDevicePower(ctx context.Context) (*common.ResourceList[device_power.Data], error) {
+	return handlers.Get[common.ResourceList[device_power.Data]](ctx, basePath, m.client)
+}
+
+func (m *Manager) GetDevicePower(ctx context.Context, id string) (*device_power.Data, error) {
+	return handlers.GetSingularResource[device_power.Data](id, basePath+"/"+id, ctx, m.client, "device_power")
+}
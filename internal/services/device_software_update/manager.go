@@ -0,0 +1,39 @@
+package device_software_update
+
+import (
+	"context"
+	"github.com/richseviora/huego/internal/client/handlers"
+	"github.com/richseviora/huego/pkg/logger"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/device_software_update"
+)
+
+const basePath = "/clip/v2/resource/device_software_update"
+
+type Manager struct {
+	client common.RequestProcessor
+	logger logger.Logger
+}
+
+var (
+	_ device_software_update.Service = &Manager{}
+)
+
+func NewManager(client common.RequestProcessor, logger logger.Logger) *Manager {
+	return &Manager{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (m *Manager) GetAllDeviceSoftwareUpdates(ctx context.Context) (*common.ResourceList[device_software_update.Data], error) {
+	return handlers.Get[common.ResourceList[device_software_update.Data]](ctx, basePath, m.client)
+}
+
+func (m *Manager) GetDeviceSoftwareUpdate(ctx context.Context, id string) (*device_software_update.Data, error) {
+	return handlers.GetSingularResource[device_software_update.Data](id, basePath+"/"+id, ctx, m.client, "device_software_update")
+}
+
+func (m *Manager) InstallDeviceSoftwareUpdate(ctx context.Context, id string) (*common.Reference, error) {
+	return handlers.UpdateResource(basePath+"/"+id, ctx, device_software_update.UpdateRequest{Install: true}, m.client, "device_software_update")
+}
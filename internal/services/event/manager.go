@@ -0,0 +1,133 @@
+package event
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/richseviora/huego/pkg/logger"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/event"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const streamPath = "/eventstream/clip/v2"
+
+// DefaultReconnectDelay is how long the manager waits before reconnecting a dropped stream.
+const DefaultReconnectDelay = 5 * time.Second
+
+// StreamProcessor is a RequestProcessor that can also hold a request open indefinitely, which the event stream needs.
+type StreamProcessor interface {
+	common.RequestProcessor
+	DoStream(ctx context.Context, req *http.Request) (*http.Response, error)
+}
+
+type Manager struct {
+	client         StreamProcessor
+	logger         logger.Logger
+	ReconnectDelay time.Duration
+}
+
+var (
+	_ event.Service = &Manager{}
+)
+
+func NewManager(client StreamProcessor, logger logger.Logger) *Manager {
+	return &Manager{
+		client:         client,
+		logger:         logger,
+		ReconnectDelay: DefaultReconnectDelay,
+	}
+}
+
+func (m *Manager) Subscribe(ctx context.Context) (<-chan event.Event, error) {
+	body, err := m.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	events := make(chan event.Event)
+	go func() {
+		defer close(events)
+		for {
+			err := readEvents(ctx, body, events)
+			_ = body.Close()
+			if ctx.Err() != nil {
+				return
+			}
+			m.logger.Warn("Event stream disconnected", map[string]interface{}{
+				"error": err,
+			})
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(m.ReconnectDelay):
+				}
+				body, err = m.connect(ctx)
+				if err == nil {
+					break
+				}
+				m.logger.Error("Failed to reconnect event stream", map[string]interface{}{
+					"error": err,
+				})
+			}
+		}
+	}()
+	return events, nil
+}
+
+func (m *Manager) connect(ctx context.Context) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, m.client.BaseURL()+streamPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := m.client.DoStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("event stream request failed with status: %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// readEvents parses server-sent events from r until it ends. Each message's data is a JSON array of events.
+func readEvents(ctx context.Context, r io.Reader, events chan<- event.Event) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" {
+			if value, ok := strings.CutPrefix(line, "data:"); ok {
+				data.WriteString(strings.TrimPrefix(value, " "))
+			}
+			continue
+		}
+		if data.Len() == 0 {
+			continue
+		}
+		var batch []event.Event
+		err := json.Unmarshal([]byte(data.String()), &batch)
+		data.Reset()
+		if err != nil {
+			return fmt.Errorf("failed to decode event: %w", err)
+		}
+		for _, e := range batch {
+			select {
+			case events <- e:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return io.EOF
+}
@@ -0,0 +1,49 @@
+package light_level
+
+import (
+	"context"
+	"github.com/richseviora/huego/internal/client/handlers"
+	common2 "github.com/richseviora/huego/internal/services/common"
+	"github.com/richseviora/huego/pkg/logger"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/light_level"
+)
+
+const basePath = "/clip/v2/resource/light_level"
+
+type Manager struct {
+	client common.RequestProcessor
+	logger logger.Logger
+}
+
+func (m *Manager) CollectionPath() string {
+	return basePath
+}
+
+func (m *Manager) ResourcePath(id string) string {
+	return basePath + "/" + id
+}
+
+func (m *Manager) GetAllLightLevels(ctx context.Context) (*common.ResourceList[light_level.Data], error) {
+	return handlers.Get[common.ResourceList[light_level.Data]](ctx, m.CollectionPath(), m.client)
+}
+
+func (m *Manager) GetLightLevel(ctx context.Context, id string) (*light_level.Data, error) {
+	return handlers.GetSingularResource[light_level.Data](id, m.ResourcePath(id), ctx, m.client, "light_level")
+}
+
+func (m *Manager) UpdateLightLevel(ctx context.Context, id string, update light_level.UpdateRequest) (*common.Reference, error) {
+	return handlers.UpdateResource(m.ResourcePath(id), ctx, update, m.client, "light_level")
+}
+
+var (
+	_ light_level.Service      = &Manager{}
+	_ common2.ResourcePathable = &Manager{}
+)
+
+func NewManager(client common.RequestProcessor, logger logger.Logger) *Manager {
+	return &Manager{
+		client: client,
+		logger: logger,
+	}
+}
@@ -0,0 +1,45 @@
+package relative_rotary
+
+import (
+	"context"
+	"github.com/richseviora/huego/internal/client/handlers"
+	common2 "github.com/richseviora/huego/internal/services/common"
+	"github.com/richseviora/huego/pkg/logger"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/relative_rotary"
+)
+
+const basePath = "/clip/v2/resource/relative_rotary"
+
+type Manager struct {
+	client common.RequestProcessor
+	logger logger.Logger
+}
+
+func (m *Manager) CollectionPath() string {
+	return basePath
+}
+
+func (m *Manager) ResourcePath(id string) string {
+	return basePath + "/" + id
+}
+
+func (m *Manager) GetAllRelativeRotaries(ctx context.Context) (*common.ResourceList[relative_rotary.Data], error) {
+	return handlers.Get[common.ResourceList[relative_rotary.Data]](ctx, m.CollectionPath(), m.client)
+}
+
+func (m *Manager) GetRelativeRotary(ctx context.Context, id string) (*relative_rotary.Data, error) {
+	return handlers.GetSingularResource[relative_rotary.Data](id, m.ResourcePath(id), ctx, m.client, "relative_rotary")
+}
+
+var (
+	_ relative_rotary.Service  = &Manager{}
+	_ common2.ResourcePathable = &Manager{}
+)
+
+func NewManager(client common.RequestProcessor, logger logger.Logger) *Manager {
+	return &Manager{
+		client: client,
+		logger: logger,
+	}
+}
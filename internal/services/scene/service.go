@@ -6,7 +6,11 @@ import (
 	"github.com/richseviora/huego/internal/client/handlers"
 	"github.com/richseviora/huego/pkg/logger"
 	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/device"
+	"github.com/richseviora/huego/pkg/resources/light"
+	"github.com/richseviora/huego/pkg/resources/room"
 	scene2 "github.com/richseviora/huego/pkg/resources/scene"
+	"github.com/richseviora/huego/pkg/resources/zone"
 )
 
 var (
@@ -14,14 +18,24 @@ var (
 )
 
 type SceneManager struct {
-	client common.RequestProcessor
-	logger logger.Logger
+	client  common.RequestProcessor
+	logger  logger.Logger
+	rooms   room.RoomService
+	zones   zone.ZoneService
+	devices device.Service
+	lights  light.LightService
 }
 
-func NewSceneService(client common.RequestProcessor, logger logger.Logger) *SceneManager {
+// NewSceneService creates a scene service using the client's room, zone, device and light services to resolve the
+// lights in a scene's group.
+func NewSceneService(client common.RequestProcessor, logger logger.Logger, rooms room.RoomService, zones zone.ZoneService, devices device.Service, lights light.LightService) *SceneManager {
 	return &SceneManager{
-		client: client,
-		logger: logger,
+		client:  client,
+		logger:  logger,
+		rooms:   rooms,
+		zones:   zones,
+		devices: devices,
+		lights:  lights,
 	}
 }
 
@@ -39,6 +53,10 @@ func (s *SceneManager) UpdateScene(ctx context.Context, id string, scene scene2.
 	return handlers.UpdateResource(url, ctx, scene, s.client, "scene")
 }
 
+func (s *SceneManager) RecallScene(ctx context.Context, id string, recall scene2.Recall) (*common.Reference, error) {
+	return s.UpdateScene(ctx, id, scene2.SceneUpdate{Recall: &recall})
+}
+
 func (s *SceneManager) CreateScene(ctx context.Context, scene scene2.SceneCreate) (*common.Reference, error) {
 	return handlers.CreateResource("/clip/v2/resource/scene", ctx, scene, s.client, "scene")
 }
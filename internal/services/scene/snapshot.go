@@ -0,0 +1,136 @@
+package scene
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/richseviora/huego/pkg/resources/client"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/light"
+	scene2 "github.com/richseviora/huego/pkg/resources/scene"
+)
+
+// SnapshotRoomToScene reads the current state of every light in the room or zone and returns a SceneCreate that
+// reproduces it. The scene is not posted to the bridge.
+func (s *SceneManager) SnapshotRoomToScene(ctx context.Context, groupID string, name string) (*scene2.SceneCreate, error) {
+	group, children, err := s.resolveGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	lightIDs, err := s.resolveLightIDs(ctx, children)
+	if err != nil {
+		return nil, err
+	}
+	lights, err := s.lights.GetAllLights(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lightsByID := make(map[string]light.Light, len(lights.Data))
+	for _, l := range lights.Data {
+		lightsByID[l.ID] = l
+	}
+
+	actions := make([]scene2.ActionTarget, 0, len(lightIDs))
+	for _, id := range lightIDs {
+		l, ok := lightsByID[id]
+		if !ok {
+			s.logger.Warn("Light in group not found", map[string]interface{}{
+				"groupID": groupID,
+				"lightID": id,
+			})
+			continue
+		}
+		actions = append(actions, ActionTargetFromLight(l))
+	}
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("group %s has no lights to snapshot", groupID)
+	}
+
+	return &scene2.SceneCreate{
+		Metadata: scene2.SceneMetadata{Name: name},
+		Actions:  actions,
+		Group:    group,
+	}, nil
+}
+
+// resolveGroup looks the ID up as a room and then as a zone, returning the group reference and its children.
+func (s *SceneManager) resolveGroup(ctx context.Context, groupID string) (common.Reference, []common.Reference, error) {
+	r, err := s.rooms.GetRoom(ctx, groupID)
+	if err == nil {
+		return common.Reference{RID: r.ID, RType: "room"}, r.Children, nil
+	}
+	if !errors.Is(err, client.ErrNotFound) {
+		return common.Reference{}, nil, err
+	}
+	z, err := s.zones.GetZone(ctx, groupID)
+	if err != nil {
+		return common.Reference{}, nil, err
+	}
+	return common.Reference{RID: z.ID, RType: "zone"}, z.Children, nil
+}
+
+// resolveLightIDs maps group children to light IDs. Rooms reference devices, zones usually reference lights directly.
+func (s *SceneManager) resolveLightIDs(ctx context.Context, children []common.Reference) ([]string, error) {
+	var lightIDs []string
+	needsDevices := false
+	for _, child := range children {
+		if child.RType == "device" {
+			needsDevices = true
+			break
+		}
+	}
+	devicesByID := make(map[string][]string)
+	if needsDevices {
+		devices, err := s.devices.GetAllDevices(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range devices.Data {
+			for _, service := range d.Services {
+				if service.Rtype == "light" {
+					devicesByID[d.ID] = append(devicesByID[d.ID], service.Rid)
+				}
+			}
+		}
+	}
+	for _, child := range children {
+		switch child.RType {
+		case "light":
+			lightIDs = append(lightIDs, child.RID)
+		case "device":
+			lightIDs = append(lightIDs, devicesByID[child.RID]...)
+		}
+	}
+	return lightIDs, nil
+}
+
+// ActionTargetFromLight converts the current state of a light into a scene action. Lights in colour temperature mode
+// report a valid mirek value, otherwise the xy colour is used.
+func ActionTargetFromLight(l light.Light) scene2.ActionTarget {
+	action := scene2.Action{
+		On: &scene2.On{On: l.On.On},
+	}
+	if l.Dimming != nil && l.Dimming.Brightness > 0 {
+		action.Dimming = &common.Dimming{Brightness: l.Dimming.Brightness}
+	}
+	switch {
+	case l.Gradient != nil && len(l.Gradient.Points) > 0:
+		action.Gradient = &scene2.ColorGradient{
+			Points: l.Gradient.Points,
+			Mode:   l.Gradient.Mode,
+		}
+	case l.ColorTemp != nil && l.ColorTemp.MirekValid && l.ColorTemp.Mirek > 0:
+		action.ColorTemperature = &light.ColorTemperature{Mirek: l.ColorTemp.Mirek}
+	case l.Color != nil && (l.Color.XY.X != 0 || l.Color.XY.Y != 0):
+		action.Color = &light.Color{XY: l.Color.XY}
+	}
+	if l.EffectsV2 != nil && l.EffectsV2.Status.Effect != "" && l.EffectsV2.Status.Effect != light.NoEffect {
+		action.EffectsV2 = &scene2.LightEffectV2{
+			Action: scene2.LightEffectV2Action{Effect: l.EffectsV2.Status.Effect},
+		}
+	}
+	return scene2.ActionTarget{
+		Target: scene2.Target{Rid: l.ID, Rtype: "light"},
+		Action: action,
+	}
+}
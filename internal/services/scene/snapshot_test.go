@@ -0,0 +1,89 @@
+package scene
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/richseviora/huego/pkg/resources/color"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/light"
+	scene2 "github.com/richseviora/huego/pkg/resources/scene"
+	"testing"
+)
+
+func TestActionTargetFromLight(t *testing.T) {
+	xy := color.XYCoord{X: 0.3691, Y: 0.3719}
+	minDimLevel := 0.2
+	ctLight := light.Light{
+		ID:        "ct",
+		On:        light.LightOn{On: true},
+		Dimming:   &light.DimmingInfo{Brightness: 80, MinDimLevel: &minDimLevel},
+		Color:     &light.ColorInfo{XY: xy},
+		ColorTemp: &light.ColorTemperatureInfo{Mirek: 366, MirekValid: true},
+	}
+
+	xyLight := light.Light{
+		ID:      "xy",
+		On:      light.LightOn{On: true},
+		Dimming: &light.DimmingInfo{Brightness: 50},
+		Color:   &light.ColorInfo{XY: xy},
+	}
+
+	gradientLight := xyLight
+	gradientLight.ID = "gradient"
+	gradientLight.Gradient = &light.GradientInfo{
+		Points: []light.GradientPoint{{Color: light.Color{XY: xy}}},
+		Mode:   "interpolated_palette",
+	}
+
+	effectLight := xyLight
+	effectLight.ID = "effect"
+	effectLight.EffectsV2 = &light.EffectsV2Info{}
+	effectLight.EffectsV2.Status.Effect = "candle"
+
+	offPlug := light.Light{ID: "plug"}
+
+	testCases := []struct {
+		name     string
+		input    light.Light
+		expected scene2.Action
+	}{
+		{"uses colour temperature when mirek is valid", ctLight, scene2.Action{
+			On:               &scene2.On{On: true},
+			Dimming:          &common.Dimming{Brightness: 80},
+			ColorTemperature: &light.ColorTemperature{Mirek: 366},
+		}},
+		{"uses xy colour when mirek is not valid", xyLight, scene2.Action{
+			On:      &scene2.On{On: true},
+			Dimming: &common.Dimming{Brightness: 50},
+			Color:   &light.Color{XY: xy},
+		}},
+		{"uses gradient when points are set", gradientLight, scene2.Action{
+			On:      &scene2.On{On: true},
+			Dimming: &common.Dimming{Brightness: 50},
+			Gradient: &scene2.ColorGradient{
+				Points: []light.GradientPoint{{Color: light.Color{XY: xy}}},
+				Mode:   "interpolated_palette",
+			},
+		}},
+		{"includes active effect", effectLight, scene2.Action{
+			On:        &scene2.On{On: true},
+			Dimming:   &common.Dimming{Brightness: 50},
+			Color:     &light.Color{XY: xy},
+			EffectsV2: &scene2.LightEffectV2{Action: scene2.LightEffectV2Action{Effect: "candle"}},
+		}},
+		{"only sets on for lights without dimming or colour", offPlug, scene2.Action{
+			On: &scene2.On{On: false},
+		}},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ActionTargetFromLight(tt.input)
+			expected := scene2.ActionTarget{
+				Target: scene2.Target{Rid: tt.input.ID, Rtype: "light"},
+				Action: tt.expected,
+			}
+			if diff := cmp.Diff(expected, result); diff != "" {
+				t.Errorf("Mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
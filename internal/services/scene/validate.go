@@ -0,0 +1,44 @@
+package scene
+
+import (
+	"context"
+	"fmt"
+	"github.com/richseviora/huego/pkg/resources/common"
+	scene2 "github.com/richseviora/huego/pkg/resources/scene"
+)
+
+func (s *SceneManager) ValidateScene(ctx context.Context, scene scene2.SceneCreate) error {
+	children, err := s.groupChildren(ctx, scene.Group)
+	if err != nil {
+		return err
+	}
+	lightIDs, err := s.resolveLightIDs(ctx, children)
+	if err != nil {
+		return err
+	}
+	lights, err := s.lights.GetAllLights(ctx)
+	if err != nil {
+		return err
+	}
+	return scene2.ValidateActions(scene.Actions, lightIDs, lights.Data)
+}
+
+// groupChildren loads the children of the room or zone the reference points to.
+func (s *SceneManager) groupChildren(ctx context.Context, group common.Reference) ([]common.Reference, error) {
+	switch group.RType {
+	case "room":
+		r, err := s.rooms.GetRoom(ctx, group.RID)
+		if err != nil {
+			return nil, err
+		}
+		return r.Children, nil
+	case "zone":
+		z, err := s.zones.GetZone(ctx, group.RID)
+		if err != nil {
+			return nil, err
+		}
+		return z.Children, nil
+	default:
+		return nil, fmt.Errorf("scene group must be a room or zone, got %q", group.RType)
+	}
+}
@@ -0,0 +1,69 @@
+package smart_scene
+
+import (
+	"context"
+	"github.com/richseviora/huego/internal/client/handlers"
+	common2 "github.com/richseviora/huego/internal/services/common"
+	"github.com/richseviora/huego/pkg/logger"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/smart_scene"
+)
+
+const basePath = "/clip/v2/resource/smart_scene"
+
+type Manager struct {
+	client common.RequestProcessor
+	logger logger.Logger
+}
+
+func (m *Manager) GetAllSmartScenes(ctx context.Context) (*common.ResourceList[smart_scene.Data], error) {
+	return handlers.Get[common.ResourceList[smart_scene.Data]](ctx, m.CollectionPath(), m.client)
+}
+
+func (m *Manager) GetSmartScene(ctx context.Context, id string) (*smart_scene.Data, error) {
+	return handlers.GetSingularResource[smart_scene.Data](id, m.ResourcePath(id), ctx, m.client, "smart_scene")
+}
+
+func (m *Manager) CreateSmartScene(ctx context.Context, create smart_scene.CreateRequest) (*common.Reference, error) {
+	return handlers.CreateResource(m.CollectionPath(), ctx, create, m.client, "smart_scene")
+}
+
+func (m *Manager) UpdateSmartScene(ctx context.Context, id string, update smart_scene.UpdateRequest) (*common.Reference, error) {
+	return handlers.UpdateResource(m.ResourcePath(id), ctx, update, m.client, "smart_scene")
+}
+
+func (m *Manager) DeleteSmartScene(ctx context.Context, id string) error {
+	return handlers.Delete(ctx, m.ResourcePath(id), m.client)
+}
+
+func (m *Manager) ActivateSmartScene(ctx context.Context, id string) (*common.Reference, error) {
+	return m.UpdateSmartScene(ctx, id, smart_scene.UpdateRequest{
+		Recall: &smart_scene.Recall{Action: smart_scene.RecallActionActivate},
+	})
+}
+
+func (m *Manager) DeactivateSmartScene(ctx context.Context, id string) (*common.Reference, error) {
+	return m.UpdateSmartScene(ctx, id, smart_scene.UpdateRequest{
+		Recall: &smart_scene.Recall{Action: smart_scene.RecallActionDeactivate},
+	})
+}
+
+func (m *Manager) CollectionPath() string {
+	return basePath
+}
+
+func (m *Manager) ResourcePath(id string) string {
+	return basePath + "/" + id
+}
+
+var (
+	_ smart_scene.Service      = &Manager{}
+	_ common2.ResourcePathable = &Manager{}
+)
+
+func NewManager(client common.RequestProcessor, logger logger.Logger) *Manager {
+	return &Manager{
+		client: client,
+		logger: logger,
+	}
+}
@@ -0,0 +1,131 @@
+package smart_scene
+
+import (
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/scene"
+	"github.com/richseviora/huego/pkg/resources/smart_scene"
+	"testing"
+)
+
+var response = `{
+    "errors": [],
+    "data": [
+        {
+            "id": "6a6e8c5f-2f4a-4a2c-9e3b-0f3c9a2b1d11",
+            "type": "smart_scene",
+            "metadata": {
+                "name": "Natural light",
+                "image": {
+                    "rid": "eb014820-a902-4652-8ca7-6e29c03b87a1",
+                    "rtype": "public_image"
+                }
+            },
+            "group": {
+                "rid": "0d960eab-68c6-4ed7-8c0d-a24ca756d58e",
+                "rtype": "room"
+            },
+            "week_timeslots": [
+                {
+                    "timeslots": [
+                        {
+                            "start_time": {
+                                "kind": "time",
+                                "time": {
+                                    "hour": 7,
+                                    "minute": 0,
+                                    "second": 0
+                                }
+                            },
+                            "target": {
+                                "rid": "68b39f81-1c15-4c82-bd0b-ab28606f3d2e",
+                                "rtype": "scene"
+                            }
+                        },
+                        {
+                            "start_time": {
+                                "kind": "sunset"
+                            },
+                            "target": {
+                                "rid": "0825511a-e048-40c9-a989-b0231b8a50e5",
+                                "rtype": "scene"
+                            }
+                        }
+                    ],
+                    "recurrence": [
+                        "monday",
+                        "tuesday"
+                    ]
+                }
+            ],
+            "transition_duration": 60000,
+            "active_timeslot": {
+                "timeslot_id": 1,
+                "weekday": "monday"
+            },
+            "state": "active"
+        }
+    ]
+}
+`
+
+func TestManager_JSONParse(t *testing.T) {
+	t.Run("parses correctly", func(t *testing.T) {
+		expected := smart_scene.Data{
+			ID:   "6a6e8c5f-2f4a-4a2c-9e3b-0f3c9a2b1d11",
+			Type: "smart_scene",
+			Metadata: scene.SceneMetadata{
+				Name: "Natural light",
+				Image: &scene.Image{
+					Rid:   "eb014820-a902-4652-8ca7-6e29c03b87a1",
+					Rtype: "public_image",
+				},
+			},
+			Group: common.Reference{
+				RID:   "0d960eab-68c6-4ed7-8c0d-a24ca756d58e",
+				RType: "room",
+			},
+			WeekTimeslots: []smart_scene.WeekTimeslots{
+				{
+					Timeslots: []smart_scene.Timeslot{
+						{
+							StartTime: smart_scene.StartTime{
+								Kind: smart_scene.StartTimeKindTime,
+								Time: &smart_scene.Time{Hour: 7},
+							},
+							Target: common.Reference{
+								RID:   "68b39f81-1c15-4c82-bd0b-ab28606f3d2e",
+								RType: "scene",
+							},
+						},
+						{
+							StartTime: smart_scene.StartTime{
+								Kind: smart_scene.StartTimeKindSunset,
+							},
+							Target: common.Reference{
+								RID:   "0825511a-e048-40c9-a989-b0231b8a50e5",
+								RType: "scene",
+							},
+						},
+					},
+					Recurrence: []smart_scene.Weekday{smart_scene.Monday, smart_scene.Tuesday},
+				},
+			},
+			TransitionDuration: 60000,
+			ActiveTimeslot: &smart_scene.ActiveTimeslot{
+				TimeslotID: 1,
+				Weekday:    smart_scene.Monday,
+			},
+			State: smart_scene.StateActive,
+		}
+		var result common.ResourceList[smart_scene.Data]
+		err := json.Unmarshal(([]byte)(response), &result)
+		if err != nil {
+			t.Error(err)
+		}
+		if diff := cmp.Diff(expected, result.Data[0]); diff != "" {
+			t.Errorf("Mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package tamper
+
+import (
+	"context"
+	"github.com/richseviora/huego/internal/client/handlers"
+	common2 "github.com/richseviora/huego/internal/services/common"
+	"github.com/richseviora/huego/pkg/logger"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/tamper"
+)
+
+const basePath = "/clip/v2/resource/tamper"
+
+type Manager struct {
+	client common.RequestProcessor
+	logger logger.Logger
+}
+
+func (m *Manager) CollectionPath() string {
+	return basePath
+}
+
+func (m *Manager) ResourcePath(id string) string {
+	return basePath + "/" + id
+}
+
+func (m *Manager) GetAllTampers(ctx context.Context) (*common.ResourceList[tamper.Data], error) {
+	return handlers.Get[common.ResourceList[tamper.Data]](ctx, m.CollectionPath(), m.client)
+}
+
+func (m *Manager) GetTamper(ctx context.Context, id string) (*tamper.Data, error) {
+	return handlers.GetSingularResource[tamper.Data](id, m.ResourcePath(id), ctx, m.client, "tamper")
+}
+
+var (
+	_ tamper.Service           = &Manager{}
+	_ common2.ResourcePathable = &Manager{}
+)
+
+func NewManager(client common.RequestProcessor, logger logger.Logger) *Manager {
+	return &Manager{
+		client: client,
+		logger: logger,
+	}
+}
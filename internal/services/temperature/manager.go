@@ -0,0 +1,49 @@
+package temperature
+
+import (
+	"context"
+	"github.com/richseviora/huego/internal/client/handlers"
+	common2 "github.com/richseviora/huego/internal/services/common"
+	"github.com/richseviora/huego/pkg/logger"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/temperature"
+)
+
+const basePath = "/clip/v2/resource/temperature"
+
+type Manager struct {
+	client common.RequestProcessor
+	logger logger.Logger
+}
+
+func (m *Manager) CollectionPath() string {
+	return basePath
+}
+
+func (m *Manager) ResourcePath(id string) string {
+	return basePath + "/" + id
+}
+
+func (m *Manager) GetAllTemperatures(ctx context.Context) (*common.ResourceList[temperature.Data], error) {
+	return handlers.Get[common.ResourceList[temperature.Data]](ctx, m.CollectionPath(), m.client)
+}
+
+func (m *Manager) GetTemperature(ctx context.Context, id string) (*temperature.Data, error) {
+	return handlers.GetSingularResource[temperature.Data](id, m.ResourcePath(id), ctx, m.client, "temperature")
+}
+
+func (m *Manager) UpdateTemperature(ctx context.Context, id string, update temperature.UpdateRequest) (*common.Reference, error) {
+	return handlers.UpdateResource(m.ResourcePath(id), ctx, update, m.client, "temperature")
+}
+
+var (
+	_ temperature.Service      = &Manager{}
+	_ common2.ResourcePathable = &Manager{}
+)
+
+func NewManager(client common.RequestProcessor, logger logger.Logger) *Manager {
+	return &Manager{
+		client: client,
+		logger: logger,
+	}
+}
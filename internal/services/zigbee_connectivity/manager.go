@@ -31,3 +31,7 @@ func (m Manager) GetAllZigbeeConnectivity(ctx context.Context) (*common.Resource
 func (m Manager) GetZigbeeConnectivity(ctx context.Context, id string) (*zigbee_connectivity.Data, error) {
 	return handlers.GetSingularResource[zigbee_connectivity.Data](id, "/clip/v2/resource/zigbee_connectivity/"+id, ctx, m.client, "zigbee_connectivity")
 }
+
+func (m Manager) UpdateZigbeeConnectivity(ctx context.Context, id string, update zigbee_connectivity.UpdateRequest) (*common.Reference, error) {
+	return handlers.UpdateResource("/clip/v2/resource/zigbee_connectivity/"+id, ctx, update, m.client, "zigbee_connectivity")
+}
@@ -0,0 +1,49 @@
+package zigbee_device_discovery
+
+import (
+	"context"
+	"github.com/richseviora/huego/internal/client/handlers"
+	common2 "github.com/richseviora/huego/internal/services/common"
+	"github.com/richseviora/huego/pkg/logger"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/zigbee_device_discovery"
+)
+
+const basePath = "/clip/v2/resource/zigbee_device_discovery"
+
+type Manager struct {
+	client common.RequestProcessor
+	logger logger.Logger
+}
+
+func (m *Manager) CollectionPath() string {
+	return basePath
+}
+
+func (m *Manager) ResourcePath(id string) string {
+	return basePath + "/" + id
+}
+
+func (m *Manager) GetAllZigbeeDeviceDiscovery(ctx context.Context) (*common.ResourceList[zigbee_device_discovery.Data], error) {
+	return handlers.Get[common.ResourceList[zigbee_device_discovery.Data]](ctx, m.CollectionPath(), m.client)
+}
+
+func (m *Manager) GetZigbeeDeviceDiscovery(ctx context.Context, id string) (*zigbee_device_discovery.Data, error) {
+	return handlers.GetSingularResource[zigbee_device_discovery.Data](id, m.ResourcePath(id), ctx, m.client, "zigbee_device_discovery")
+}
+
+func (m *Manager) UpdateZigbeeDeviceDiscovery(ctx context.Context, id string, update zigbee_device_discovery.UpdateRequest) (*common.Reference, error) {
+	return handlers.UpdateResource(m.ResourcePath(id), ctx, update, m.client, "zigbee_device_discovery")
+}
+
+var (
+	_ zigbee_device_discovery.Service = &Manager{}
+	_ common2.ResourcePathable        = &Manager{}
+)
+
+func NewManager(client common.RequestProcessor, logger logger.Logger) *Manager {
+	return &Manager{
+		client: client,
+		logger: logger,
+	}
+}
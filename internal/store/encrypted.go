@@ -0,0 +1,201 @@
+package store
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+const (
+	// DefaultKDFIterations is the number of PBKDF2-SHA256 iterations used for new files
+	DefaultKDFIterations = 600000
+	// MinKDFIterations is the lowest iteration count accepted, both for new files and from existing ones, so a
+	// tampered file cannot weaken the key its secrets are written under
+	MinKDFIterations     = 1000
+	encryptedFileVersion = 1
+	encryptedKDF         = "pbkdf2-sha256"
+	saltLength           = 16
+	keyLength            = 32
+)
+
+// ErrInvalidPassphrase is returned when an encrypted file cannot be decrypted with the passphrase
+var ErrInvalidPassphrase = errors.New("invalid passphrase or corrupted key store")
+
+// ErrInvalidKeyStoreFile is returned when the KDF parameters or nonce of an encrypted file are not acceptable
+var ErrInvalidKeyStoreFile = errors.New("invalid key store file")
+
+// encryptedFile is the on-disk format of EncryptedKeyStore. Only the KDF parameters are stored in clear text.
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// EncryptedKeyStore implements KeyStore interface with persistent storage encrypted with AES-256-GCM, using a key
+// derived from a passphrase with PBKDF2-SHA256. The whole store is rewritten with a fresh nonce on every change.
+type EncryptedKeyStore struct {
+	filepath   string
+	data       map[string]interface{}
+	gcm        cipher.AEAD
+	salt       []byte
+	iterations int
+	mu         sync.RWMutex
+}
+
+// EncryptedOption defines functional options for NewEncryptedKeyStore
+type EncryptedOption func(*EncryptedKeyStore)
+
+// WithKDFIterations overrides DefaultKDFIterations for new files, down to MinKDFIterations. Existing files keep the
+// iterations they were created with.
+func WithKDFIterations(iterations int) EncryptedOption {
+	return func(s *EncryptedKeyStore) {
+		s.iterations = iterations
+	}
+}
+
+// NewEncryptedKeyStore creates a new EncryptedKeyStore instance, decrypting the file if it exists. It returns
+// ErrInvalidPassphrase if the passphrase does not match the one the file was written with.
+func NewEncryptedKeyStore(filepath string, passphrase []byte, opts ...EncryptedOption) (*EncryptedKeyStore, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase must not be empty")
+	}
+	store := &EncryptedKeyStore{
+		filepath:   filepath,
+		data:       make(map[string]interface{}),
+		iterations: DefaultKDFIterations,
+	}
+	for _, opt := range opts {
+		opt(store)
+	}
+
+	raw, err := os.ReadFile(filepath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var file encryptedFile
+	if err == nil {
+		if err := json.Unmarshal(raw, &file); err != nil {
+			return nil, err
+		}
+		if file.Version != encryptedFileVersion || file.KDF != encryptedKDF {
+			return nil, fmt.Errorf("unsupported key store version %d with KDF %q", file.Version, file.KDF)
+		}
+		store.salt = file.Salt
+		store.iterations = file.Iterations
+		if len(store.salt) == 0 {
+			return nil, fmt.Errorf("%w: missing salt", ErrInvalidKeyStoreFile)
+		}
+	} else {
+		store.salt = make([]byte, saltLength)
+		if _, err := rand.Read(store.salt); err != nil {
+			return nil, err
+		}
+	}
+
+	if store.iterations < MinKDFIterations {
+		return nil, fmt.Errorf("%w: %d KDF iterations, at least %d required", ErrInvalidKeyStoreFile, store.iterations, MinKDFIterations)
+	}
+	block, err := aes.NewCipher(pbkdf2SHA256(passphrase, store.salt, store.iterations, keyLength))
+	if err != nil {
+		return nil, err
+	}
+	if store.gcm, err = cipher.NewGCM(block); err != nil {
+		return nil, err
+	}
+	if file.Ciphertext != nil {
+		if len(file.Nonce) != store.gcm.NonceSize() {
+			return nil, fmt.Errorf("%w: nonce is %d bytes, expected %d", ErrInvalidKeyStoreFile, len(file.Nonce), store.gcm.NonceSize())
+		}
+		plaintext, err := store.gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+		if err != nil {
+			return nil, ErrInvalidPassphrase
+		}
+		if err := json.Unmarshal(plaintext, &store.data); err != nil {
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+func (s *EncryptedKeyStore) save() error {
+	plaintext, err := json.Marshal(s.data)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, s.gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	data, err := json.Marshal(encryptedFile{
+		Version:    encryptedFileVersion,
+		KDF:        encryptedKDF,
+		Iterations: s.iterations,
+		Salt:       s.salt,
+		Nonce:      nonce,
+		Ciphertext: s.gcm.Seal(nil, nonce, plaintext, nil),
+	})
+	if err != nil {
+		return err
+	}
+	return writeFile(s.filepath, data)
+}
+
+func (s *EncryptedKeyStore) Get(key string) (interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if value, ok := s.data[key]; ok {
+		return value, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+func (s *EncryptedKeyStore) Set(key string, value interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[key] = value
+	return s.save()
+}
+
+func (s *EncryptedKeyStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data, key)
+	return s.save()
+}
+
+func (s *EncryptedKeyStore) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data = make(map[string]interface{})
+	return s.save()
+}
+
+func (s *EncryptedKeyStore) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.data))
+	for k := range s.data {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+var (
+	_ KeyStore = &DiskKeyStore{}
+	_ KeyStore = &MemoryKeyStore{}
+	_ KeyStore = &EncryptedKeyStore{}
+	_ KeyStore = &EnvKeyStore{}
+)
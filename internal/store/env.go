@@ -0,0 +1,88 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// DefaultEnvPrefix is the prefix of the variables read by EnvKeyStore when none is given
+const DefaultEnvPrefix = "HUEGO_"
+
+// ErrReadOnly is returned when writing to a store that cannot be modified
+var ErrReadOnly = errors.New("key store is read-only")
+
+// IsReadOnly reports whether s rejects writes with ErrReadOnly
+func IsReadOnly(s KeyStore) bool {
+	r, ok := s.(interface{ ReadOnly() bool })
+	return ok && r.ReadOnly()
+}
+
+// EnvKeyStore implements KeyStore interface over environment variables, so secrets can be injected by the deployment
+// instead of being written to disk. A key maps to the prefix followed by the key in upper case, with every character
+// other than letters and digits replaced by an underscore: "ecb5fafffe111111/username" is read from
+// HUEGO_ECB5FAFFFE111111_USERNAME. The store is read-only.
+type EnvKeyStore struct {
+	prefix string
+}
+
+// NewEnvKeyStore creates a new EnvKeyStore instance reading variables starting with prefix, or DefaultEnvPrefix if
+// prefix is empty
+func NewEnvKeyStore(prefix string) *EnvKeyStore {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+	return &EnvKeyStore{prefix: prefix}
+}
+
+// VariableName returns the environment variable holding the key
+func (s *EnvKeyStore) VariableName(key string) string {
+	var b strings.Builder
+	b.WriteString(s.prefix)
+	for _, r := range strings.ToUpper(key) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+func (s *EnvKeyStore) Get(key string) (interface{}, error) {
+	if value, ok := os.LookupEnv(s.VariableName(key)); ok {
+		return value, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+// ReadOnly is always true, as the store cannot change the environment of the process that started it
+func (s *EnvKeyStore) ReadOnly() bool {
+	return true
+}
+
+func (s *EnvKeyStore) Set(string, interface{}) error {
+	return ErrReadOnly
+}
+
+func (s *EnvKeyStore) Delete(string) error {
+	return ErrReadOnly
+}
+
+func (s *EnvKeyStore) Clear() error {
+	return ErrReadOnly
+}
+
+// Keys returns the lower-cased variable names without the prefix. As the mapping from keys to variable names is
+// lossy, these are not the keys originally written, but Get accepts them. Copy and Export refuse the store for that
+// reason.
+func (s *EnvKeyStore) Keys() []string {
+	var keys []string
+	for _, env := range os.Environ() {
+		name, _, _ := strings.Cut(env, "=")
+		if strings.HasPrefix(name, s.prefix) && len(name) > len(s.prefix) {
+			keys = append(keys, strings.ToLower(strings.TrimPrefix(name, s.prefix)))
+		}
+	}
+	return keys
+}
@@ -0,0 +1,59 @@
+package store
+
+import "sync"
+
+// MemoryKeyStore implements KeyStore interface without persistence, for tests and short-lived processes
+type MemoryKeyStore struct {
+	data map[string]interface{}
+	mu   sync.RWMutex
+}
+
+// NewMemoryKeyStore creates a new empty MemoryKeyStore instance
+func NewMemoryKeyStore() *MemoryKeyStore {
+	return &MemoryKeyStore{data: make(map[string]interface{})}
+}
+
+func (s *MemoryKeyStore) Get(key string) (interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if value, ok := s.data[key]; ok {
+		return value, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+func (s *MemoryKeyStore) Set(key string, value interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[key] = value
+	return nil
+}
+
+func (s *MemoryKeyStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data, key)
+	return nil
+}
+
+func (s *MemoryKeyStore) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data = make(map[string]interface{})
+	return nil
+}
+
+func (s *MemoryKeyStore) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.data))
+	for k := range s.data {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -28,7 +28,8 @@ type KeyStore interface {
 	Keys() []string
 }
 
-// DiskKeyStore implements KeyStore interface with persistent storage
+// DiskKeyStore implements KeyStore interface with persistent storage. Values are stored in plain text, in a file
+// readable only by its owner; use EncryptedKeyStore where secrets must not be stored in clear text.
 type DiskKeyStore struct {
 	filepath string
 	data     map[string]interface{}
@@ -61,7 +62,7 @@ func (s *DiskKeyStore) save() error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(s.filepath, data, 0644)
+	return writeFile(s.filepath, data)
 }
 
 func (s *DiskKeyStore) Get(key string) (interface{}, error) {
@@ -0,0 +1,153 @@
+package store
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestPBKDF2SHA256(t *testing.T) {
+	// Test vectors from RFC 7914, section 11.
+	tests := []struct {
+		passphrase, salt string
+		iterations       int
+		expected         string
+	}{
+		{"passwd", "salt", 1, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"},
+		{"Password", "NaCl", 80000, "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d"},
+	}
+	for _, tt := range tests {
+		result := hex.EncodeToString(pbkdf2SHA256([]byte(tt.passphrase), []byte(tt.salt), tt.iterations, 64))
+		if result != tt.expected {
+			t.Errorf("pbkdf2SHA256(%s, %s, %d) = %s, want %s", tt.passphrase, tt.salt, tt.iterations, result, tt.expected)
+		}
+	}
+}
+
+func TestEncryptedKeyStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	s, err := NewEncryptedKeyStore(path, []byte("secret"), WithKDFIterations(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("b1/username", "application-key"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("application-key")) || bytes.Contains(data, []byte("b1/username")) {
+		t.Errorf("file contains the credentials in clear text: %s", data)
+	}
+
+	reopened, err := NewEncryptedKeyStore(path, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := reopened.Get("b1/username"); err != nil || value != "application-key" {
+		t.Errorf("Get() = %v, %v, want application-key, nil", value, err)
+	}
+	if _, err := NewEncryptedKeyStore(path, []byte("wrong")); !errors.Is(err, ErrInvalidPassphrase) {
+		t.Errorf("NewEncryptedKeyStore() error = %v, want %v", err, ErrInvalidPassphrase)
+	}
+}
+
+func TestEncryptedKeyStore_RejectsTamperedFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	s, err := NewEncryptedKeyStore(path, []byte("secret"), WithKDFIterations(MinKDFIterations))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("b1/username", "application-key"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		modify func(f *encryptedFile)
+	}{
+		{name: "truncated nonce", modify: func(f *encryptedFile) { f.Nonce = f.Nonce[:4] }},
+		{name: "missing nonce", modify: func(f *encryptedFile) { f.Nonce = nil }},
+		{name: "weak iterations", modify: func(f *encryptedFile) { f.Iterations = 1 }},
+		{name: "zero iterations", modify: func(f *encryptedFile) { f.Iterations = 0 }},
+		{name: "missing salt", modify: func(f *encryptedFile) { f.Salt = nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var file encryptedFile
+			if err := json.Unmarshal(data, &file); err != nil {
+				t.Fatal(err)
+			}
+			tt.modify(&file)
+			tampered := filepath.Join(t.TempDir(), "keys.json")
+			raw, _ := json.Marshal(file)
+			if err := os.WriteFile(tampered, raw, 0600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := NewEncryptedKeyStore(tampered, []byte("secret")); !errors.Is(err, ErrInvalidKeyStoreFile) {
+				t.Errorf("NewEncryptedKeyStore() error = %v, want %v", err, ErrInvalidKeyStoreFile)
+			}
+		})
+	}
+	if _, err := NewEncryptedKeyStore(filepath.Join(t.TempDir(), "new.json"), []byte("secret"), WithKDFIterations(1)); !errors.Is(err, ErrInvalidKeyStoreFile) {
+		t.Errorf("NewEncryptedKeyStore() error = %v, want %v", err, ErrInvalidKeyStoreFile)
+	}
+}
+
+func TestEnvKeyStore(t *testing.T) {
+	t.Setenv("HUEGO_TEST_ECB5FAFFFE111111_USERNAME", "application-key")
+	s := NewEnvKeyStore("HUEGO_TEST_")
+	if value, err := s.Get("ecb5fafffe111111/username"); err != nil || value != "application-key" {
+		t.Errorf("Get() = %v, %v, want application-key, nil", value, err)
+	}
+	if diff := cmp.Diff([]string{"ecb5fafffe111111_username"}, s.Keys()); diff != "" {
+		t.Errorf("Keys() mismatch (-want +got):\n%s", diff)
+	}
+	if err := s.Set("key", "value"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Set() error = %v, want %v", err, ErrReadOnly)
+	}
+	if !IsReadOnly(s) || IsReadOnly(NewMemoryKeyStore()) {
+		t.Errorf("IsReadOnly() is only true for the environment store")
+	}
+	if err := Copy(NewMemoryKeyStore(), s); !errors.Is(err, ErrNotTransferable) {
+		t.Errorf("Copy() error = %v, want %v", err, ErrNotTransferable)
+	}
+	if err := Export(&bytes.Buffer{}, s); !errors.Is(err, ErrNotTransferable) {
+		t.Errorf("Export() error = %v, want %v", err, ErrNotTransferable)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	src := NewMemoryKeyStore()
+	_ = src.Set("b1/username", "key1")
+	_ = src.Set("b2/username", "key2")
+	var buf bytes.Buffer
+	if err := Export(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+	dst, err := NewDiskKeyStore(filepath.Join(t.TempDir(), "keys.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Import(&buf, dst); err != nil {
+		t.Fatal(err)
+	}
+	keys := dst.Keys()
+	sort.Strings(keys)
+	if diff := cmp.Diff([]string{"b1/username", "b2/username"}, keys); diff != "" {
+		t.Errorf("Keys() mismatch (-want +got):\n%s", diff)
+	}
+	if value, err := dst.Get("b2/username"); err != nil || value != "key2" {
+		t.Errorf("Get() = %v, %v, want key2, nil", value, err)
+	}
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ErrNotTransferable is returned when copying or exporting a store whose keys are not the ones originally written,
+// such as EnvKeyStore. The entries would be written under keys nothing reads.
+var ErrNotTransferable = errors.New("key store cannot be copied or exported")
+
+func checkTransferable(s KeyStore) error {
+	if _, ok := s.(*EnvKeyStore); ok {
+		return fmt.Errorf("%w: environment variable names do not map back to keys", ErrNotTransferable)
+	}
+	return nil
+}
+
+// Copy writes every entry of src to dst, overwriting entries with the same key. It is used to move credentials
+// between backends, for example from a plain file into an encrypted one.
+func Copy(dst, src KeyStore) error {
+	if err := checkTransferable(src); err != nil {
+		return err
+	}
+	keys := src.Keys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, err := src.Get(key)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", key, err)
+		}
+		if err := dst.Set(key, value); err != nil {
+			return fmt.Errorf("failed to write %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Export writes every entry of s to w as a JSON object. The output is not encrypted.
+func Export(w io.Writer, s KeyStore) error {
+	if err := checkTransferable(s); err != nil {
+		return err
+	}
+	data := make(map[string]interface{})
+	for _, key := range s.Keys() {
+		value, err := s.Get(key)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", key, err)
+		}
+		data[key] = value
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+// Import reads a JSON object written by Export and sets each entry in s.
+func Import(r io.Reader, s KeyStore) error {
+	source := NewMemoryKeyStore()
+	if err := json.NewDecoder(r).Decode(&source.data); err != nil {
+		return err
+	}
+	return Copy(s, source)
+}
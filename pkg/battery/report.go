@@ -0,0 +1,112 @@
+package battery
+
+import (
+	"context"
+	"github.com/richseviora/huego/pkg/resources/client"
+	"github.com/richseviora/huego/pkg/resources/device_power"
+	"sort"
+)
+
+// Status is the battery of one device, joined with the device's name and model.
+type Status struct {
+	DeviceID    string
+	DeviceName  string
+	ModelID     string
+	ProductName string
+	State       device_power.BatteryState
+	// Level is the remaining charge in percent, nil when the device does not report it.
+	Level *int
+}
+
+// Load returns the battery status of every battery powered device, sorted by device name. Mains powered devices are
+// left out.
+func Load(ctx context.Context, c client.HueServiceClient) ([]Status, error) {
+	power, err := c.DevicePowerService().GetAllDevicePower(ctx)
+	if err != nil {
+		return nil, err
+	}
+	devices, err := c.DeviceService().GetAllDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []Status
+	for _, p := range power.Data {
+		if !p.HasBattery() {
+			continue
+		}
+		s := Status{DeviceID: p.Owner.RID, State: p.PowerState.BatteryState, Level: p.PowerState.BatteryLevel}
+		for _, d := range devices.Data {
+			if d.ID == p.Owner.RID {
+				s.DeviceName = d.Metadata.Name
+				s.ModelID = d.ProductData.ModelID
+				s.ProductName = d.ProductData.ProductName
+				break
+			}
+		}
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].DeviceName != result[j].DeviceName {
+			return result[i].DeviceName < result[j].DeviceName
+		}
+		return result[i].DeviceID < result[j].DeviceID
+	})
+	return result, nil
+}
+
+type Severity string
+
+const (
+	SeverityLow      Severity = "low"
+	SeverityCritical Severity = "critical"
+)
+
+// Thresholds are the battery levels, in percent, at or below which a device is reported.
+type Thresholds struct {
+	Low      int
+	Critical int
+}
+
+// DefaultThresholds flags batteries at 20% and below, and treats 5% and below as critical.
+var DefaultThresholds = Thresholds{Low: 20, Critical: 5}
+
+type Entry struct {
+	Status
+	Severity Severity
+}
+
+// Report returns the devices whose battery is at or below the thresholds, critical entries first. A device is also
+// reported when the bridge itself flags its battery as low or critical, whichever is more severe.
+func Report(statuses []Status, thresholds Thresholds) []Entry {
+	var result []Entry
+	for _, s := range statuses {
+		severity := severityOf(s, thresholds)
+		if severity == "" {
+			continue
+		}
+		result = append(result, Entry{Status: s, Severity: severity})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Severity == SeverityCritical && result[j].Severity != SeverityCritical
+	})
+	return result
+}
+
+// LowBatteryReport loads the battery status from the bridge and reports the devices at or below the thresholds.
+func LowBatteryReport(ctx context.Context, c client.HueServiceClient, thresholds Thresholds) ([]Entry, error) {
+	statuses, err := Load(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	return Report(statuses, thresholds), nil
+}
+
+func severityOf(s Status, thresholds Thresholds) Severity {
+	if s.State == device_power.BatteryCritical || (s.Level != nil && *s.Level <= thresholds.Critical) {
+		return SeverityCritical
+	}
+	if s.State == device_power.BatteryLow || (s.Level != nil && *s.Level <= thresholds.Low) {
+		return SeverityLow
+	}
+	return ""
+}
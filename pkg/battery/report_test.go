@@ -0,0 +1,33 @@
+package battery
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/richseviora/huego/pkg/resources/device_power"
+	"testing"
+)
+
+func level(l int) *int {
+	return &l
+}
+
+func TestReport(t *testing.T) {
+	statuses := []Status{
+		{DeviceID: "d1", DeviceName: "Dimmer", State: device_power.BatteryNormal, Level: level(80)},
+		{DeviceID: "d2", DeviceName: "Hallway sensor", State: device_power.BatteryNormal, Level: level(15)},
+		{DeviceID: "d3", DeviceName: "Porch sensor", State: device_power.BatteryNormal, Level: level(3)},
+		{DeviceID: "d4", DeviceName: "Tap dial", State: device_power.BatteryLow},
+		{DeviceID: "d5", DeviceName: "Wall switch", State: device_power.BatteryCritical, Level: level(30)},
+	}
+	expected := []Entry{
+		{Status: statuses[2], Severity: SeverityCritical},
+		{Status: statuses[4], Severity: SeverityCritical},
+		{Status: statuses[1], Severity: SeverityLow},
+		{Status: statuses[3], Severity: SeverityLow},
+	}
+	if diff := cmp.Diff(expected, Report(statuses, DefaultThresholds)); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+	if got := Report(statuses, Thresholds{Low: 2, Critical: 1}); len(got) != 2 {
+		t.Errorf("Report() with lower thresholds returned %d entries, want 2", len(got))
+	}
+}
@@ -1,9 +1,13 @@
 package pkg
 
 import (
+	"context"
 	"github.com/richseviora/huego/internal/bridge"
+	"github.com/richseviora/huego/internal/store"
 	"github.com/richseviora/huego/pkg/logger"
 	client2 "github.com/richseviora/huego/pkg/resources/client"
+	"io"
+	"time"
 )
 
 // NewClientWithoutPath constructs a HueServiceClient with the IP address and key supplied.
@@ -30,4 +34,92 @@ func NewClientProviderWithPath(path string, logger logger.Logger) (client2.Persi
 	return provider, nil
 }
 
+// NewClientProviderWithStore is NewClientProviderWithPath with application keys kept in keys instead of the cache
+// file at path. Keys already in the cache file are moved to keys. Read-only stores are rejected.
+func NewClientProviderWithStore(path string, keys KeyStore, logger logger.Logger) (client2.PersistentClientProvider, error) {
+	if logger == nil {
+		logger = NoOpLogger
+	}
+	return bridge.NewBuilderWithStore(path, keys, logger)
+}
+
 var NoOpLogger = logger.NoopLogger{}
+
+// Bridge is a bridge found by discovery.
+type Bridge = bridge.Bridge
+
+type PairingProgress = bridge.PairingProgress
+
+type PairingCredentials = bridge.Credentials
+
+type PairOption = bridge.PairOption
+
+// KeyStore persists the credentials returned by PairBridge.
+type KeyStore = store.KeyStore
+
+// PairBridge registers an application with the bridge, polling until its link button is pressed or ctx is done.
+func PairBridge(ctx context.Context, b Bridge, appName, instanceName string, progress func(PairingProgress), opts ...PairOption) (*PairingCredentials, error) {
+	return bridge.PairBridge(ctx, b, appName, instanceName, progress, opts...)
+}
+
+// RotateKey registers a new application key, persists it with persist and any key store option, verifies it and
+// revokes oldKey. The link button has to be pressed while it runs.
+func RotateKey(ctx context.Context, b Bridge, oldKey, appName, instanceName string, persist func(*PairingCredentials) error, progress func(PairingProgress), opts ...PairOption) (*PairingCredentials, error) {
+	return bridge.RotateKey(ctx, b, oldKey, appName, instanceName, persist, progress, opts...)
+}
+
+// ErrRevokeFailed is returned by RotateKey when the new key works but the old one could not be revoked.
+var ErrRevokeFailed = bridge.ErrRevokeFailed
+
+// WithPairingKeyStore saves the credentials to the key store once pairing completes.
+func WithPairingKeyStore(s KeyStore) PairOption {
+	return bridge.WithPairingKeyStore(s)
+}
+
+// WithPairingPollInterval overrides how often PairBridge retries.
+func WithPairingPollInterval(interval time.Duration) PairOption {
+	return bridge.WithPairingPollInterval(interval)
+}
+
+// NewMemoryKeyStore returns a key store that is not persisted.
+func NewMemoryKeyStore() KeyStore {
+	return store.NewMemoryKeyStore()
+}
+
+// NewFileKeyStore returns a key store persisted in plain text at path.
+func NewFileKeyStore(path string) (KeyStore, error) {
+	s, err := store.NewDiskKeyStore(path)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewEncryptedKeyStore returns a key store persisted at path, encrypted with a key derived from passphrase.
+func NewEncryptedKeyStore(path string, passphrase []byte) (KeyStore, error) {
+	s, err := store.NewEncryptedKeyStore(path, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewEnvKeyStore returns a read-only key store over environment variables starting with prefix.
+func NewEnvKeyStore(prefix string) KeyStore {
+	return store.NewEnvKeyStore(prefix)
+}
+
+// CopyKeys writes every entry of src to dst. Stores read from the environment cannot be copied.
+func CopyKeys(dst, src KeyStore) error {
+	return store.Copy(dst, src)
+}
+
+// ExportKeys writes every entry of s to w as unencrypted JSON.
+func ExportKeys(w io.Writer, s KeyStore) error {
+	return store.Export(w, s)
+}
+
+// ImportKeys reads entries written by ExportKeys into s.
+func ImportKeys(r io.Reader, s KeyStore) error {
+	return store.Import(r, s)
+}
@@ -0,0 +1,95 @@
+package controls
+
+import (
+	"context"
+	"github.com/richseviora/huego/pkg/resources/button"
+	"github.com/richseviora/huego/pkg/resources/client"
+	"github.com/richseviora/huego/pkg/resources/event"
+	"github.com/richseviora/huego/pkg/resources/relative_rotary"
+	"time"
+)
+
+// ButtonPress is a button event from a dimmer switch, smart button or Tap Dial.
+type ButtonPress struct {
+	ButtonID string
+	DeviceID string
+	// ControlID is the position of the button on the device, starting at 1.
+	ControlID int
+	Event     button.Event
+	Updated   time.Time
+}
+
+// Rotation is a turn of a Tap Dial's rotary ring.
+type Rotation struct {
+	RotaryID string
+	DeviceID string
+	Action   relative_rotary.Action
+	Rotation relative_rotary.Rotation
+	Updated  time.Time
+}
+
+// Handler receives input events. Either function may be nil.
+type Handler struct {
+	OnButton   func(ButtonPress)
+	OnRotation func(Rotation)
+}
+
+// Listen subscribes to the event stream and calls the handler for every button press and rotation until ctx is done.
+// Handlers run on the listening goroutine, so slow handlers delay later events.
+func Listen(ctx context.Context, c client.HueServiceClient, h Handler) error {
+	buttons, err := c.ButtonService().GetAllButtons(ctx)
+	if err != nil {
+		return err
+	}
+	controlIDs := make(map[string]int)
+	for _, b := range buttons.Data {
+		controlIDs[b.ID] = b.Metadata.ControlID
+	}
+	// Cancelling on return closes the stream when a decode error ends Listen before the caller's ctx is done.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events, err := c.EventService().Subscribe(ctx)
+	if err != nil {
+		return err
+	}
+	for e := range events {
+		if e.Type != event.Update {
+			continue
+		}
+		if h.OnButton != nil {
+			updates, err := event.Decode[button.Data](e, "button")
+			if err != nil {
+				return err
+			}
+			for _, b := range updates {
+				controlID, ok := controlIDs[b.ID]
+				if !ok {
+					// Buttons of devices paired after Listen started are looked up on first use.
+					if full, err := c.ButtonService().GetButton(ctx, b.ID); err == nil {
+						controlID = full.Metadata.ControlID
+						controlIDs[b.ID] = controlID
+					}
+				}
+				ev, updated := b.LastEvent()
+				if ev == "" {
+					continue
+				}
+				h.OnButton(ButtonPress{ButtonID: b.ID, DeviceID: b.Owner.RID, ControlID: controlID, Event: ev, Updated: updated})
+			}
+		}
+		if h.OnRotation != nil {
+			updates, err := event.Decode[relative_rotary.Data](e, "relative_rotary")
+			if err != nil {
+				return err
+			}
+			for _, r := range updates {
+				ev, updated := r.LastEvent()
+				if ev == nil {
+					continue
+				}
+				h.OnRotation(Rotation{RotaryID: r.ID, DeviceID: r.Owner.RID, Action: ev.Action, Rotation: ev.Rotation, Updated: updated})
+			}
+		}
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,85 @@
+package controls
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-cmp/cmp"
+	client2 "github.com/richseviora/huego/internal/client"
+	"github.com/richseviora/huego/pkg/logger"
+	"github.com/richseviora/huego/pkg/resources/button"
+	"github.com/richseviora/huego/pkg/resources/relative_rotary"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const buttons = `{"errors":[],"data":[{"id":"b1","owner":{"rid":"d1","rtype":"device"},"metadata":{"control_id":2},"button":{},"type":"button"}]}`
+
+const events = `[
+  {"creationtime":"2024-01-01T10:00:00Z","id":"e1","type":"update","data":[
+    {"id":"b1","owner":{"rid":"d1","rtype":"device"},"button":{"last_event":"initial_press","button_report":{"updated":"2024-01-01T10:00:00Z","event":"initial_press"}},"type":"button"},
+    {"id":"l1","owner":{"rid":"d2","rtype":"device"},"on":{"on":true},"type":"light"}
+  ]},
+  {"creationtime":"2024-01-01T10:00:01Z","id":"e2","type":"update","data":[
+    {"id":"rr1","owner":{"rid":"d1","rtype":"device"},"relative_rotary":{"rotary_report":{"updated":"2024-01-01T10:00:01Z","action":"start","rotation":{"direction":"counter_clock_wise","steps":30,"duration":400}}},"type":"relative_rotary"}
+  ]}
+]`
+
+func TestListen(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /clip/v2/resource/button", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(buttons))
+	})
+	mux.HandleFunc("GET /eventstream/clip/v2", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprintf(w, ": hi\n\nid: 1:0\ndata: %s\n\n", compact(events))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	c := client2.NewAPIClient(server.URL, "key", logger.NoopLogger{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var presses []ButtonPress
+	var rotations []Rotation
+	err := Listen(ctx, c, Handler{
+		OnButton: func(p ButtonPress) {
+			presses = append(presses, p)
+		},
+		OnRotation: func(r Rotation) {
+			rotations = append(rotations, r)
+			cancel()
+		},
+	})
+	if err != context.Canceled {
+		t.Fatalf("Listen() error = %v, want %v", err, context.Canceled)
+	}
+	expectedPresses := []ButtonPress{{
+		ButtonID: "b1", DeviceID: "d1", ControlID: 2, Event: button.InitialPress,
+		Updated: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+	}}
+	if diff := cmp.Diff(expectedPresses, presses); diff != "" {
+		t.Errorf("presses mismatch (-want +got):\n%s", diff)
+	}
+	expectedRotations := []Rotation{{
+		RotaryID: "rr1", DeviceID: "d1", Action: relative_rotary.Start,
+		Rotation: relative_rotary.Rotation{Direction: relative_rotary.CounterClockWise, Steps: 30, Duration: 400},
+		Updated:  time.Date(2024, 1, 1, 10, 0, 1, 0, time.UTC),
+	}}
+	if diff := cmp.Diff(expectedRotations, rotations); diff != "" {
+		t.Errorf("rotations mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func compact(s string) string {
+	result := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\n' {
+			result = append(result, s[i])
+		}
+	}
+	return string(result)
+}
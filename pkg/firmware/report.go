@@ -0,0 +1,117 @@
+package firmware
+
+import (
+	"context"
+	"github.com/richseviora/huego/pkg/resources/client"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/device_software_update"
+	"sort"
+)
+
+// Device is the firmware status of one device.
+type Device struct {
+	// Home identifies the bridge the device belongs to when reports span several homes.
+	Home            string
+	DeviceID        string
+	Name            string
+	ModelID         string
+	SoftwareVersion string
+	// UpdateID is the device_software_update service of the device, empty if it has none.
+	UpdateID string
+	State    device_software_update.State
+	Problems []string
+}
+
+// Load returns the firmware status of every device on the bridge, tagged with home.
+func Load(ctx context.Context, c client.HueServiceClient, home string) ([]Device, error) {
+	devices, err := c.DeviceService().GetAllDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	updates, err := c.DeviceSoftwareUpdateService().GetAllDeviceSoftwareUpdates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byOwner := make(map[string]device_software_update.Data)
+	for _, u := range updates.Data {
+		byOwner[u.Owner.RID] = u
+	}
+	var result []Device
+	for _, d := range devices.Data {
+		fd := Device{
+			Home:            home,
+			DeviceID:        d.ID,
+			Name:            d.Metadata.Name,
+			ModelID:         d.ProductData.ModelID,
+			SoftwareVersion: d.ProductData.SoftwareVersion,
+		}
+		if u, ok := byOwner[d.ID]; ok {
+			fd.UpdateID = u.ID
+			fd.State = u.State
+			fd.Problems = u.Problems
+		}
+		result = append(result, fd)
+	}
+	return result, nil
+}
+
+// Entry compares a device against the newest firmware seen for its model.
+type Entry struct {
+	Device
+	LatestVersion string
+	Outdated      bool
+}
+
+// Report compares every device to the newest SoftwareVersion seen for the same ModelID across all the devices given,
+// so passing devices from several homes finds homes that lag behind. Entries are sorted by model, home and name.
+func Report(devices []Device) []Entry {
+	latest := make(map[string]string)
+	for _, d := range devices {
+		if d.ModelID == "" || d.SoftwareVersion == "" {
+			continue
+		}
+		if common.CompareVersions(d.SoftwareVersion, latest[d.ModelID]) > 0 {
+			latest[d.ModelID] = d.SoftwareVersion
+		}
+	}
+	result := make([]Entry, 0, len(devices))
+	for _, d := range devices {
+		e := Entry{Device: d, LatestVersion: latest[d.ModelID]}
+		e.Outdated = e.LatestVersion != "" && common.CompareVersions(d.SoftwareVersion, e.LatestVersion) < 0
+		result = append(result, e)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.ModelID != b.ModelID {
+			return a.ModelID < b.ModelID
+		}
+		if a.Home != b.Home {
+			return a.Home < b.Home
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.DeviceID < b.DeviceID
+	})
+	return result
+}
+
+// InstallReady triggers installation on every device whose update is ready to install and for which include returns
+// true, so callers can limit a rollout to some models or rooms. It returns the IDs of the devices it triggered and
+// stops at the first error.
+func InstallReady(ctx context.Context, c client.HueServiceClient, devices []Device, include func(Device) bool) ([]string, error) {
+	var triggered []string
+	for _, d := range devices {
+		if d.State != device_software_update.ReadyToInstall || d.UpdateID == "" {
+			continue
+		}
+		if include != nil && !include(d) {
+			continue
+		}
+		if _, err := c.DeviceSoftwareUpdateService().InstallDeviceSoftwareUpdate(ctx, d.UpdateID); err != nil {
+			return triggered, err
+		}
+		triggered = append(triggered, d.DeviceID)
+	}
+	return triggered, nil
+}
@@ -0,0 +1,22 @@
+package firmware
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	devices := []Device{
+		{Home: "b", DeviceID: "d1", Name: "Lamp", ModelID: "LCA001", SoftwareVersion: "1.93.11"},
+		{Home: "a", DeviceID: "d2", Name: "Ceiling", ModelID: "LCA001", SoftwareVersion: "1.116.3"},
+		{Home: "a", DeviceID: "d3", Name: "Switch", ModelID: "RWL022", SoftwareVersion: "2.45.2"},
+	}
+	expected := []Entry{
+		{Device: devices[1], LatestVersion: "1.116.3"},
+		{Device: devices[0], LatestVersion: "1.116.3", Outdated: true},
+		{Device: devices[2], LatestVersion: "2.45.2"},
+	}
+	if diff := cmp.Diff(expected, Report(devices)); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+}
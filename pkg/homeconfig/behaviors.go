@@ -0,0 +1,200 @@
+package homeconfig
+
+import (
+	"context"
+	"github.com/richseviora/huego/pkg/resources/behavior_instance"
+	"github.com/richseviora/huego/pkg/resources/client"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"strings"
+)
+
+func (p *planner) motionScriptID() string {
+	for _, s := range p.state.Scripts {
+		if s.Metadata.Name == MotionSensorScriptName {
+			return s.ID
+		}
+	}
+	return ""
+}
+
+func (p *planner) planBehaviors() {
+	scriptID := p.motionScriptID()
+	if scriptID == "" && (len(p.cfg.Behaviors) > 0 || p.cfg.Prune) {
+		if len(p.cfg.Behaviors) > 0 {
+			p.errorf("behaviour script %q not found", MotionSensorScriptName)
+		}
+		return
+	}
+	managed := make(map[string]bool)
+	for _, cb := range p.cfg.Behaviors {
+		b := cb
+		if b.DarkThreshold == 0 {
+			b.DarkThreshold = DefaultDarkThreshold
+		}
+		sensorID, err := p.device(b.Sensor)
+		if err != nil {
+			p.errorf("behaviour %q: sensor: %w", b.Name, err)
+			continue
+		}
+		if _, ok := p.groupLights[groupKey("room", b.Room)]; !ok {
+			p.errorf("behaviour %q: room %q not found", b.Name, b.Room)
+			continue
+		}
+		var existing *behavior_instance.Data
+		for i := range p.state.Behaviors {
+			d := &p.state.Behaviors[i]
+			if (b.ID != "" && d.ID == b.ID) || (b.ID == "" && d.ScriptID == scriptID && d.Metadata.Name == b.Name) {
+				existing = d
+				break
+			}
+		}
+		if b.ID != "" && existing == nil {
+			p.errorf("behaviour %q with ID %s not found", b.Name, b.ID)
+			continue
+		}
+
+		configuration := func(r *resolver) (*behavior_instance.Configuration, error) {
+			roomID, err := r.group("room", b.Room)
+			if err != nil {
+				return nil, err
+			}
+			result := &behavior_instance.Configuration{
+				Settings: behavior_instance.Settings{
+					DaylightSensitivity: behavior_instance.DaylightSensitivity{
+						DarkThreshold: b.DarkThreshold,
+						Offset:        defaultDarkOffset,
+					},
+				},
+				Source: common.Reference{RID: sensorID, RType: "device"},
+				Where:  []behavior_instance.Where{{Group: common.Reference{RID: roomID, RType: "room"}}},
+			}
+			for _, slot := range b.Timeslots {
+				sceneID, err := r.scene("room", b.Room, slot.Scene)
+				if err != nil {
+					return nil, err
+				}
+				result.When.Timeslots = append(result.When.Timeslots, behavior_instance.TimeSlots{
+					OnMotion: behavior_instance.OnMotion{
+						RecallSingle: []behavior_instance.RecallSingle{
+							{Action: behavior_instance.Action{Recall: common.Reference{RID: sceneID, RType: "scene"}}},
+						},
+					},
+					OnNoMotion: behavior_instance.OnNoMotion{
+						After:        behavior_instance.After{Minutes: slot.OffAfterMinutes},
+						RecallSingle: []behavior_instance.RecallSingleNoMotion{{Action: "all_off"}},
+					},
+					StartTime: behavior_instance.StartTime{
+						Time: behavior_instance.Time{Hour: slot.Hour, Minute: slot.Minute},
+						Type: "time",
+					},
+				})
+			}
+			return result, nil
+		}
+
+		if existing == nil {
+			p.behaviors = append(p.behaviors, Change{
+				Action:       Create,
+				ResourceType: "behavior_instance",
+				Name:         b.Name,
+				Diffs:        diffJSON(nil, "configuration", nil, b),
+				apply: func(ctx context.Context, c client.HueServiceClient, r *resolver) error {
+					config, err := configuration(r)
+					if err != nil {
+						return err
+					}
+					_, err = c.BehaviorInstanceService().CreateBehaviorInstance(ctx, behavior_instance.CreateRequest{
+						ScriptID:      scriptID,
+						Enabled:       b.Enabled,
+						Configuration: *config,
+						Metadata:      &behavior_instance.Metadata{Name: b.Name},
+					})
+					return err
+				},
+			})
+			continue
+		}
+
+		managed[existing.ID] = true
+		current := p.describeBehavior(*existing)
+		var diffs []FieldDiff
+		diffs = diffString(diffs, "name", current.Name, b.Name)
+		diffs = diffJSON(diffs, "enabled", current.Enabled, b.Enabled)
+		diffs = diffString(diffs, "sensor", current.Sensor, p.deviceNames[sensorID])
+		diffs = diffString(diffs, "room", current.Room, b.Room)
+		diffs = diffJSON(diffs, "dark_threshold", current.DarkThreshold, b.DarkThreshold)
+		diffs = diffJSON(diffs, "timeslots", current.Timeslots, b.Timeslots)
+		if len(diffs) == 0 {
+			continue
+		}
+		id := existing.ID
+		p.behaviors = append(p.behaviors, Change{
+			Action:       Update,
+			ResourceType: "behavior_instance",
+			ID:           id,
+			Name:         b.Name,
+			Diffs:        diffs,
+			apply: func(ctx context.Context, c client.HueServiceClient, r *resolver) error {
+				config, err := configuration(r)
+				if err != nil {
+					return err
+				}
+				enabled := b.Enabled
+				_, err = c.BehaviorInstanceService().UpdateBehaviorInstance(ctx, id, behavior_instance.UpdateRequest{
+					Configuration: config,
+					Enabled:       &enabled,
+					Metadata:      &behavior_instance.Metadata{Name: b.Name},
+				})
+				return err
+			},
+		})
+	}
+
+	if !p.cfg.Prune {
+		return
+	}
+	// Only motion behaviours are pruned, other scripts such as wake up routines are left alone.
+	for _, d := range p.state.Behaviors {
+		if managed[d.ID] || d.ScriptID != scriptID {
+			continue
+		}
+		id := d.ID
+		p.behaviorDeletes = append(p.behaviorDeletes, Change{
+			Action:       Delete,
+			ResourceType: "behavior_instance",
+			ID:           id,
+			Name:         d.Metadata.Name,
+			apply: func(ctx context.Context, c client.HueServiceClient, _ *resolver) error {
+				return c.BehaviorInstanceService().DeleteBehaviorInstance(ctx, id)
+			},
+		})
+	}
+}
+
+// describeBehavior converts a behaviour instance into the configuration form so the two can be compared.
+func (p *planner) describeBehavior(d behavior_instance.Data) MotionBehavior {
+	result := MotionBehavior{
+		ID:            d.ID,
+		Name:          d.Metadata.Name,
+		Enabled:       d.Enabled,
+		Sensor:        p.deviceNames[d.Configuration.Source.RID],
+		DarkThreshold: d.Configuration.Settings.DaylightSensitivity.DarkThreshold,
+	}
+	if len(d.Configuration.Where) > 0 {
+		if groupType, name, ok := strings.Cut(p.groupNames[d.Configuration.Where[0].Group.RID], "/"); ok && groupType == "room" {
+			result.Room = name
+		}
+	}
+	for _, slot := range d.Configuration.When.Timeslots {
+		described := MotionTimeslot{
+			Hour:            slot.StartTime.Time.Hour,
+			Minute:          slot.StartTime.Time.Minute,
+			OffAfterMinutes: slot.OnNoMotion.After.Minutes,
+		}
+		if len(slot.OnMotion.RecallSingle) > 0 {
+			described.Scene = p.sceneNames[slot.OnMotion.RecallSingle[0].Action.Recall.RID]
+		}
+		result.Timeslots = append(result.Timeslots, described)
+	}
+	return result
+}
@@ -0,0 +1,129 @@
+package homeconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/richseviora/huego/pkg/portable"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Config describes the desired state of a home. Resources are matched to the bridge by name unless an ID is pinned.
+type Config struct {
+	Rooms     []Room           `json:"rooms,omitempty"`
+	Zones     []Zone           `json:"zones,omitempty"`
+	Lights    []Light          `json:"lights,omitempty"`
+	Scenes    []Scene          `json:"scenes,omitempty"`
+	Behaviors []MotionBehavior `json:"behaviors,omitempty"`
+	// Prune deletes rooms, zones, scenes and behaviours on the bridge that are not in the configuration.
+	Prune bool `json:"prune,omitempty"`
+}
+
+// Room lists the devices, by name or ID, that belong to the room. Without an archetype, an existing room keeps its
+// own and a new room uses DefaultArchetype.
+type Room struct {
+	ID        string      `json:"id,omitempty"`
+	Name      string      `json:"name"`
+	Archetype common.Area `json:"archetype,omitempty"`
+	Devices   []string    `json:"devices"`
+}
+
+// Zone lists the lights, by name or ID, that belong to the zone. The archetype defaults as for rooms.
+type Zone struct {
+	ID        string      `json:"id,omitempty"`
+	Name      string      `json:"name"`
+	Archetype common.Area `json:"archetype,omitempty"`
+	Lights    []string    `json:"lights"`
+}
+
+// Light names a light. Without an ID the light is matched by name, so renaming requires a pinned ID.
+type Light struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+// Scene uses the portable scene format, light names refer to the names after any renames in the configuration.
+type Scene struct {
+	ID string `json:"id,omitempty"`
+	portable.Scene
+}
+
+// MotionTimeslot recalls Scene on motion from the start time, and turns the lights off after OffAfterMinutes
+// without motion.
+type MotionTimeslot struct {
+	Hour            int    `json:"hour"`
+	Minute          int    `json:"minute"`
+	Scene           string `json:"scene"`
+	OffAfterMinutes int    `json:"off_after_minutes"`
+}
+
+// MotionBehavior is a motion sensor behaviour that lights Room when Sensor, a device name or ID, detects motion.
+type MotionBehavior struct {
+	ID        string           `json:"id,omitempty"`
+	Name      string           `json:"name"`
+	Enabled   bool             `json:"enabled"`
+	Sensor    string           `json:"sensor"`
+	Room      string           `json:"room"`
+	Timeslots []MotionTimeslot `json:"timeslots"`
+	// DarkThreshold defaults to DefaultDarkThreshold.
+	DarkThreshold int `json:"dark_threshold,omitempty"`
+}
+
+const (
+	// MotionSensorScriptName is the name of the behaviour script used for motion behaviours.
+	MotionSensorScriptName = "Motion Sensor"
+	DefaultDarkThreshold   = 14800
+	// DefaultArchetype is used for new rooms and zones that do not set an archetype.
+	DefaultArchetype  = common.Other
+	defaultDarkOffset = 7000
+)
+
+// Read decodes a JSON configuration.
+func Read(r io.Reader) (*Config, error) {
+	var result Config
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ReadYAML decodes a YAML configuration. It uses the same field names as the JSON form.
+func ReadYAML(r io.Reader) (*Config, error) {
+	var document interface{}
+	if err := yaml.NewDecoder(r).Decode(&document); err != nil {
+		return nil, err
+	}
+	// Converting through JSON keeps a single set of field tags and the same unknown field checks as Read.
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	return Read(bytes.NewReader(data))
+}
+
+// ReadFile reads a configuration from path, as YAML for .yaml and .yml files and as JSON otherwise.
+func ReadFile(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var result *Config
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		result, err = ReadYAML(f)
+	default:
+		result, err = Read(f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,36 @@
+package homeconfig
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"strings"
+	"testing"
+)
+
+func TestReadYAML(t *testing.T) {
+	cfg, err := ReadYAML(strings.NewReader(`
+prune: true
+rooms:
+  - name: Kitchen
+    archetype: kitchen
+    devices: [Ceiling bulb, Kitchen sensor]
+zones:
+  - name: Downstairs
+    lights:
+      - Ceiling
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &Config{
+		Prune: true,
+		Rooms: []Room{{Name: "Kitchen", Archetype: common.Kitchen, Devices: []string{"Ceiling bulb", "Kitchen sensor"}}},
+		Zones: []Zone{{Name: "Downstairs", Lights: []string{"Ceiling"}}},
+	}
+	if diff := cmp.Diff(expected, cfg); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := ReadYAML(strings.NewReader("rooms:\n  - name: Kitchen\n    colour: red\n")); err == nil {
+		t.Errorf("ReadYAML() error = nil, want unknown field error")
+	}
+}
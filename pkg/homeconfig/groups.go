@@ -0,0 +1,297 @@
+package homeconfig
+
+import (
+	"context"
+	"errors"
+	"github.com/richseviora/huego/pkg/resources/client"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/room"
+	"github.com/richseviora/huego/pkg/resources/zone"
+	"sort"
+)
+
+// registerGroup records the desired name and lights of a configured group, carrying over the scenes of a renamed
+// group so they can still be matched.
+func (p *planner) registerGroup(groupType, name, existingID string, lightIDs []string) {
+	key := groupKey(groupType, name)
+	if existingID != "" {
+		if oldKey, ok := p.groupNames[existingID]; ok && oldKey != key {
+			delete(p.groupLights, oldKey)
+			delete(p.resolver.groups, oldKey)
+			for _, s := range p.state.Scenes {
+				if s.Group.RID == existingID {
+					p.resolver.scenes[key+"/"+s.Metadata.Name] = s.ID
+				}
+			}
+		}
+		p.resolver.groups[key] = existingID
+		p.groupNames[existingID] = key
+	}
+	p.groupLights[key] = lightIDs
+}
+
+// archetype returns the archetype to apply to a group: the configured one, else the existing group's, else
+// DefaultArchetype for new groups. ok is false, and an error recorded, for archetypes the bridge does not know.
+func (p *planner) archetype(groupType, name string, configured common.Area, existing *common.Area) (common.Area, bool) {
+	if configured == "" {
+		if existing != nil {
+			return *existing, true
+		}
+		return DefaultArchetype, true
+	}
+	if !configured.IsKnown() {
+		p.errorf("%s %q: unknown archetype %q", groupType, name, configured)
+		return "", false
+	}
+	return configured, true
+}
+
+func (p *planner) planRooms() {
+	managed := make(map[string]bool)
+	var removals, others []Change
+	for _, cr := range p.cfg.Rooms {
+		existing := p.findRoom(cr.ID, cr.Name)
+		if cr.ID != "" && existing == nil {
+			p.errorf("room %q with ID %s not found", cr.Name, cr.ID)
+			continue
+		}
+		var existingArchetype *common.Area
+		if existing != nil {
+			existingArchetype = &existing.Metadata.Archetype
+		}
+		archetype, ok := p.archetype("room", cr.Name, cr.Archetype, existingArchetype)
+		if !ok {
+			continue
+		}
+		children := make([]common.Reference, 0, len(cr.Devices))
+		var lightIDs []string
+		for _, name := range cr.Devices {
+			id, err := p.device(name)
+			if err != nil {
+				p.errorf("room %q: %w", cr.Name, err)
+				continue
+			}
+			children = append(children, common.Reference{RID: id, RType: "device"})
+			lightIDs = append(lightIDs, p.lightsByDevice[id]...)
+		}
+		metadata := room.RoomMetadata{Name: cr.Name, Archetype: archetype}
+		desiredDevices := p.referenceNames(children)
+
+		if existing == nil {
+			p.registerGroup("room", cr.Name, "", lightIDs)
+			var diffs []FieldDiff
+			diffs = diffString(diffs, "archetype", "", archetype.String())
+			diffs = diffString(diffs, "devices", "", sortedList(desiredDevices))
+			name := cr.Name
+			others = append(others, Change{
+				Action:       Create,
+				ResourceType: "room",
+				Name:         name,
+				Diffs:        diffs,
+				apply: func(ctx context.Context, c client.HueServiceClient, r *resolver) error {
+					ref, err := c.RoomService().CreateRoom(ctx, room.RoomCreate{Children: children, Metadata: metadata})
+					if err != nil {
+						return err
+					}
+					r.groups[groupKey("room", name)] = ref.RID
+					return nil
+				},
+			})
+			continue
+		}
+
+		managed[existing.ID] = true
+		p.registerGroup("room", cr.Name, existing.ID, lightIDs)
+		var diffs []FieldDiff
+		diffs = diffString(diffs, "name", existing.Metadata.Name, cr.Name)
+		diffs = diffString(diffs, "archetype", existing.Metadata.Archetype.String(), archetype.String())
+		diffs = diffString(diffs, "devices", sortedList(p.referenceNames(existing.Children)), sortedList(desiredDevices))
+		if len(diffs) == 0 {
+			continue
+		}
+		id := existing.ID
+		change := Change{
+			Action:       Update,
+			ResourceType: "room",
+			ID:           id,
+			Name:         cr.Name,
+			Diffs:        diffs,
+			apply: func(ctx context.Context, c client.HueServiceClient, r *resolver) error {
+				return c.RoomService().UpdateRoom(ctx, room.RoomUpdate{ID: id, Children: &children, Metadata: &metadata})
+			},
+		}
+		// A device can only be in one room, so rooms giving up devices are updated before rooms receiving them.
+		if removesChildren(existing.Children, children) {
+			removals = append(removals, change)
+		} else {
+			others = append(others, change)
+		}
+	}
+	p.rooms = append(removals, others...)
+
+	if !p.cfg.Prune {
+		return
+	}
+	for _, r := range p.state.Rooms {
+		if managed[r.ID] {
+			continue
+		}
+		id := r.ID
+		p.roomDeletes = append(p.roomDeletes, Change{
+			Action:       Delete,
+			ResourceType: "room",
+			ID:           id,
+			Name:         r.Metadata.Name,
+			apply: func(ctx context.Context, c client.HueServiceClient, _ *resolver) error {
+				return c.RoomService().DeleteRoom(ctx, id)
+			},
+		})
+	}
+}
+
+func (p *planner) planZones() {
+	managed := make(map[string]bool)
+	for _, cz := range p.cfg.Zones {
+		existing := p.findZone(cz.ID, cz.Name)
+		if cz.ID != "" && existing == nil {
+			p.errorf("zone %q with ID %s not found", cz.Name, cz.ID)
+			continue
+		}
+		var existingArchetype *common.Area
+		if existing != nil {
+			existingArchetype = &existing.Metadata.Archetype
+		}
+		archetype, ok := p.archetype("zone", cz.Name, cz.Archetype, existingArchetype)
+		if !ok {
+			continue
+		}
+		children := make([]common.Reference, 0, len(cz.Lights))
+		var lightIDs []string
+		for _, name := range cz.Lights {
+			id, ok := p.lightIDs[name]
+			if !ok {
+				p.errorf("zone %q: light %q not found", cz.Name, name)
+				continue
+			}
+			children = append(children, common.Reference{RID: id, RType: "light"})
+			lightIDs = append(lightIDs, id)
+		}
+		update := &zone.ZoneCreateOrUpdate{
+			Children: children,
+			Metadata: zone.ZoneMetadata{Name: cz.Name, Archetype: archetype},
+		}
+		desiredLights := p.lightNameList(lightIDs)
+
+		if existing == nil {
+			p.registerGroup("zone", cz.Name, "", lightIDs)
+			var diffs []FieldDiff
+			diffs = diffString(diffs, "archetype", "", archetype.String())
+			diffs = diffString(diffs, "lights", "", sortedList(desiredLights))
+			name := cz.Name
+			p.zones = append(p.zones, Change{
+				Action:       Create,
+				ResourceType: "zone",
+				Name:         name,
+				Diffs:        diffs,
+				apply: func(ctx context.Context, c client.HueServiceClient, r *resolver) error {
+					result, err := c.ZoneService().CreateZone(ctx, update)
+					if err != nil {
+						return err
+					}
+					ref, err := firstReference(result)
+					if err != nil {
+						return err
+					}
+					r.groups[groupKey("zone", name)] = ref.RID
+					return nil
+				},
+			})
+			continue
+		}
+
+		managed[existing.ID] = true
+		p.registerGroup("zone", cz.Name, existing.ID, lightIDs)
+		var diffs []FieldDiff
+		diffs = diffString(diffs, "name", existing.Metadata.Name, cz.Name)
+		diffs = diffString(diffs, "archetype", existing.Metadata.Archetype.String(), archetype.String())
+		diffs = diffString(diffs, "lights", sortedList(p.referenceNames(existing.Children)), sortedList(desiredLights))
+		if len(diffs) == 0 {
+			continue
+		}
+		id := existing.ID
+		p.zones = append(p.zones, Change{
+			Action:       Update,
+			ResourceType: "zone",
+			ID:           id,
+			Name:         cz.Name,
+			Diffs:        diffs,
+			apply: func(ctx context.Context, c client.HueServiceClient, _ *resolver) error {
+				result, err := c.ZoneService().UpdateZone(ctx, id, update)
+				if err != nil {
+					return err
+				}
+				_, err = firstReference(result)
+				return err
+			},
+		})
+	}
+
+	if !p.cfg.Prune {
+		return
+	}
+	for _, z := range p.state.Zones {
+		if managed[z.ID] {
+			continue
+		}
+		id := z.ID
+		p.zoneDeletes = append(p.zoneDeletes, Change{
+			Action:       Delete,
+			ResourceType: "zone",
+			ID:           id,
+			Name:         z.Metadata.Name,
+			apply: func(ctx context.Context, c client.HueServiceClient, _ *resolver) error {
+				return c.ZoneService().DeleteZone(ctx, id)
+			},
+		})
+	}
+}
+
+// referenceNames returns the current names of the devices and lights referenced.
+func (p *planner) referenceNames(refs []common.Reference) []string {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		switch ref.RType {
+		case "device":
+			names = append(names, p.deviceNames[ref.RID])
+		case "light":
+			names = append(names, p.lightNames[ref.RID])
+		default:
+			names = append(names, ref.RType+":"+ref.RID)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func removesChildren(current, desired []common.Reference) bool {
+	kept := make(map[string]bool, len(desired))
+	for _, ref := range desired {
+		kept[ref.RID] = true
+	}
+	for _, ref := range current {
+		if !kept[ref.RID] {
+			return true
+		}
+	}
+	return false
+}
+
+func firstReference(result *common.ResourceUpdateResponse) (*common.Reference, error) {
+	if len(result.Errors) > 0 {
+		return nil, errors.New(result.Errors[0].Description)
+	}
+	if len(result.Data) == 0 {
+		return nil, errors.New("bridge returned no resource")
+	}
+	return &result.Data[0], nil
+}
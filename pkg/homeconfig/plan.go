@@ -0,0 +1,139 @@
+package homeconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/richseviora/huego/pkg/resources/client"
+	"sort"
+	"strings"
+)
+
+type ChangeAction string
+
+const (
+	Create ChangeAction = "create"
+	Update ChangeAction = "update"
+	Delete ChangeAction = "delete"
+)
+
+type FieldDiff struct {
+	Field string
+	From  string
+	To    string
+}
+
+// Change is a single create, update or delete. ID is empty for creates.
+type Change struct {
+	Action       ChangeAction
+	ResourceType string
+	ID           string
+	Name         string
+	Diffs        []FieldDiff
+	apply        func(ctx context.Context, c client.HueServiceClient, r *resolver) error
+}
+
+func (c Change) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s %q", c.Action, c.ResourceType, c.Name)
+	if c.ID != "" {
+		fmt.Fprintf(&b, " (%s)", c.ID)
+	}
+	for _, d := range c.Diffs {
+		fmt.Fprintf(&b, "\n    %s: %s -> %s", d.Field, d.From, d.To)
+	}
+	return b.String()
+}
+
+// Plan is the ordered list of changes needed to reach the configuration. Deletes come first, dependents before their
+// dependencies, followed by rooms, zones, lights, scenes and behaviours.
+type Plan struct {
+	Changes  []Change
+	resolver *resolver
+}
+
+// IsEmpty reports whether the bridge already matches the configuration.
+func (p *Plan) IsEmpty() bool {
+	return len(p.Changes) == 0
+}
+
+func (p *Plan) String() string {
+	if p.IsEmpty() {
+		return "no changes"
+	}
+	lines := make([]string, len(p.Changes))
+	for i, c := range p.Changes {
+		lines[i] = c.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// BuildPlan loads the live state from the bridge and computes the plan for the configuration.
+func BuildPlan(ctx context.Context, c client.HueServiceClient, cfg *Config) (*Plan, error) {
+	state, err := LoadState(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	return Compute(cfg, state)
+}
+
+// Apply executes the changes in order, stopping at the first failure. IDs of created rooms, zones and scenes are
+// recorded so later changes can reference them. A plan can only be applied once.
+func (p *Plan) Apply(ctx context.Context, c client.HueServiceClient) error {
+	for _, change := range p.Changes {
+		if err := change.apply(ctx, c, p.resolver); err != nil {
+			return fmt.Errorf("failed to %s %s %q: %w", change.Action, change.ResourceType, change.Name, err)
+		}
+	}
+	return nil
+}
+
+// Compute diffs the configuration against the state. Every unresolvable reference is reported in the error.
+func Compute(cfg *Config, state *State) (*Plan, error) {
+	p := newPlanner(cfg, state)
+	p.planLights()
+	p.planRooms()
+	p.planZones()
+	p.planScenes()
+	p.planBehaviors()
+	if len(p.errs) > 0 {
+		return nil, errors.Join(p.errs...)
+	}
+	var changes []Change
+	changes = append(changes, p.behaviorDeletes...)
+	changes = append(changes, p.sceneDeletes...)
+	changes = append(changes, p.zoneDeletes...)
+	changes = append(changes, p.roomDeletes...)
+	changes = append(changes, p.rooms...)
+	changes = append(changes, p.zones...)
+	changes = append(changes, p.lights...)
+	changes = append(changes, p.scenes...)
+	changes = append(changes, p.behaviors...)
+	return &Plan{Changes: changes, resolver: p.resolver}, nil
+}
+
+func diffString(diffs []FieldDiff, field, from, to string) []FieldDiff {
+	if from == to {
+		return diffs
+	}
+	return append(diffs, FieldDiff{Field: field, From: from, To: to})
+}
+
+func diffJSON(diffs []FieldDiff, field string, from, to interface{}) []FieldDiff {
+	return diffString(diffs, field, toJSON(from), toJSON(to))
+}
+
+func toJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+func sortedList(values []string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return "[" + strings.Join(sorted, ", ") + "]"
+}
@@ -0,0 +1,209 @@
+package homeconfig
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/richseviora/huego/pkg/portable"
+	"github.com/richseviora/huego/pkg/resources/behavior_script"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/device"
+	"github.com/richseviora/huego/pkg/resources/light"
+	"github.com/richseviora/huego/pkg/resources/room"
+	"github.com/richseviora/huego/pkg/resources/scene"
+	"strings"
+	"testing"
+)
+
+func testState() *State {
+	on := &scene.On{On: true}
+	return &State{
+		Devices: []device.Data{
+			{ID: "d1", Metadata: device.Metadata{Name: "Ceiling bulb"}, Services: []device.Services{{Rid: "l1", Rtype: "light"}}},
+			{ID: "d2", Metadata: device.Metadata{Name: "Lamp bulb"}, Services: []device.Services{{Rid: "l2", Rtype: "light"}}},
+			{ID: "s1", Metadata: device.Metadata{Name: "Kitchen sensor"}, Services: []device.Services{{Rid: "m1", Rtype: "motion"}}},
+		},
+		Lights: []light.Light{
+			{ID: "l1", Owner: common.Reference{RID: "d1", RType: "device"}, Metadata: light.LightMetadata{Name: "Ceiling"}},
+			{ID: "l2", Owner: common.Reference{RID: "d2", RType: "device"}, Metadata: light.LightMetadata{Name: "Lamp"}},
+		},
+		Rooms: []room.RoomData{
+			{ID: "r1", Metadata: room.RoomMetadata{Name: "Kitchen", Archetype: common.Kitchen}, Children: []common.Reference{
+				{RID: "d1", RType: "device"}, {RID: "d2", RType: "device"}, {RID: "s1", RType: "device"},
+			}},
+			{ID: "r2", Metadata: room.RoomMetadata{Name: "Office", Archetype: common.Office}},
+		},
+		Scenes: []scene.SceneData{
+			{ID: "sc1", Metadata: scene.SceneMetadata{Name: "Cooking"}, Group: common.Reference{RID: "r1", RType: "room"}, Actions: []scene.ActionTarget{
+				{Target: scene.Target{Rid: "l1", Rtype: "light"}, Action: scene.Action{On: on}},
+				{Target: scene.Target{Rid: "l2", Rtype: "light"}, Action: scene.Action{On: on}},
+			}},
+		},
+		Scripts: []behavior_script.Data{
+			{ID: "script", Metadata: behavior_script.Metadata{Name: MotionSensorScriptName}},
+		},
+	}
+}
+
+func TestCompute(t *testing.T) {
+	cfg := &Config{
+		Prune:  true,
+		Lights: []Light{{ID: "l2", Name: "Reading lamp"}},
+		Rooms: []Room{
+			{Name: "Kitchen", Archetype: common.Kitchen, Devices: []string{"Ceiling bulb", "Kitchen sensor"}},
+			{Name: "Study", Archetype: common.Office, Devices: []string{"Lamp bulb"}},
+		},
+		Zones: []Zone{{Name: "Downstairs", Archetype: common.Downstairs, Lights: []string{"Ceiling", "Reading lamp"}}},
+		Scenes: []Scene{{Scene: portable.Scene{
+			Name:    "Cooking",
+			Group:   portable.GroupRef{Type: "room", Name: "Kitchen"},
+			Actions: []portable.Action{{Light: portable.LightRef{Name: "Ceiling"}, Action: scene.Action{On: &scene.On{On: true}}}},
+		}}},
+		Behaviors: []MotionBehavior{{
+			Name:      "Kitchen motion",
+			Enabled:   true,
+			Sensor:    "Kitchen sensor",
+			Room:      "Kitchen",
+			Timeslots: []MotionTimeslot{{Hour: 8, Scene: "Cooking", OffAfterMinutes: 5}},
+		}},
+	}
+	plan, err := Compute(cfg, testState())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summary []string
+	for _, c := range plan.Changes {
+		summary = append(summary, string(c.Action)+" "+c.ResourceType+" "+c.Name)
+	}
+	expected := []string{
+		"delete room Office",
+		"update room Kitchen",
+		"create room Study",
+		"create zone Downstairs",
+		"update light Reading lamp",
+		"update scene Cooking",
+		"create behavior_instance Kitchen motion",
+	}
+	if diff := cmp.Diff(expected, summary); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+	kitchenDiffs := plan.Changes[1].Diffs
+	expectedDiffs := []FieldDiff{{Field: "devices", From: "[Ceiling bulb, Kitchen sensor, Lamp bulb]", To: "[Ceiling bulb, Kitchen sensor]"}}
+	if diff := cmp.Diff(expectedDiffs, kitchenDiffs); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCompute_NoChanges(t *testing.T) {
+	cfg := &Config{
+		Rooms: []Room{{Name: "Kitchen", Archetype: common.Kitchen, Devices: []string{"d1", "d2", "s1"}}},
+	}
+	plan, err := Compute(cfg, testState())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.IsEmpty() {
+		t.Errorf("IsEmpty() = false, plan:\n%s", plan)
+	}
+}
+
+func TestCompute_ReportsAllErrors(t *testing.T) {
+	cfg := &Config{
+		Rooms: []Room{{Name: "Kitchen", Archetype: common.Kitchen, Devices: []string{"Missing bulb"}}},
+		Zones: []Zone{{Name: "Upstairs", Archetype: common.Upstairs, Lights: []string{"Missing light"}}},
+	}
+	_, err := Compute(cfg, testState())
+	if err == nil {
+		t.Fatal("Compute() error = nil, want error")
+	}
+	for _, want := range []string{`device "Missing bulb" not found`, `light "Missing light" not found`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Compute() error = %v, want it to contain %s", err, want)
+		}
+	}
+}
+
+func TestCompute_AmbiguousDeviceNames(t *testing.T) {
+	state := testState()
+	state.Devices = append(state.Devices,
+		device.Data{ID: "s2", Metadata: device.Metadata{Name: "Hue motion sensor"}},
+		device.Data{ID: "s3", Metadata: device.Metadata{Name: "Hue motion sensor"}},
+		// A device named after another's ID must not shadow it.
+		device.Data{ID: "d3", Metadata: device.Metadata{Name: "d1"}},
+	)
+	cfg := &Config{Rooms: []Room{{Name: "Office", Archetype: common.Office, Devices: []string{"Hue motion sensor"}}}}
+	_, err := Compute(cfg, state)
+	if err == nil || !strings.Contains(err.Error(), `2 devices are named "Hue motion sensor"`) {
+		t.Errorf("Compute() error = %v, want an ambiguous device error", err)
+	}
+
+	cfg = &Config{Rooms: []Room{{Name: "Office", Archetype: common.Office, Devices: []string{"s3", "d1"}}}}
+	plan, err := Compute(cfg, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var devices string
+	for _, c := range plan.Changes {
+		for _, d := range c.Diffs {
+			if c.ID == "r2" && d.Field == "devices" {
+				devices = d.To
+			}
+		}
+	}
+	if devices != "[Ceiling bulb, Hue motion sensor]" {
+		t.Errorf("devices = %q, want the devices pinned by ID", devices)
+	}
+}
+
+func TestCompute_SceneSpeedOmitted(t *testing.T) {
+	state := testState()
+	state.Scenes[0].Speed = 0.5
+	state.Scenes[0].AutoDynamic = true
+	on := scene.Action{On: &scene.On{On: true}}
+	cfg := &Config{
+		Scenes: []Scene{{Scene: portable.Scene{
+			Name:  "Cooking",
+			Group: portable.GroupRef{Type: "room", Name: "Kitchen"},
+			Actions: []portable.Action{
+				{Light: portable.LightRef{Name: "Ceiling"}, Action: on},
+				{Light: portable.LightRef{Name: "Lamp"}, Action: on},
+			},
+		}}},
+	}
+	plan, err := Compute(cfg, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.IsEmpty() {
+		t.Errorf("IsEmpty() = false, plan:\n%s", plan)
+	}
+}
+
+func TestCompute_Archetypes(t *testing.T) {
+	cfg := &Config{
+		Rooms: []Room{
+			{Name: "Kitchen", Devices: []string{"d1", "d2", "s1"}},
+			{Name: "Study", Devices: []string{}},
+		},
+		Zones: []Zone{{Name: "Downstairs", Lights: []string{"Ceiling"}}},
+	}
+	plan, err := Compute(cfg, testState())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summary []string
+	for _, c := range plan.Changes {
+		for _, d := range c.Diffs {
+			if d.Field == "archetype" {
+				summary = append(summary, string(c.Action)+" "+c.Name+" "+d.To)
+			}
+		}
+	}
+	expected := []string{"create Study other", "create Downstairs other"}
+	if diff := cmp.Diff(expected, summary); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+
+	cfg.Rooms[1].Archetype = "ballroom"
+	if _, err := Compute(cfg, testState()); err == nil || !strings.Contains(err.Error(), `unknown archetype "ballroom"`) {
+		t.Errorf("Compute() error = %v, want unknown archetype", err)
+	}
+}
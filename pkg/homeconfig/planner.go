@@ -0,0 +1,261 @@
+package homeconfig
+
+import (
+	"context"
+	"fmt"
+	"github.com/richseviora/huego/pkg/portable"
+	"github.com/richseviora/huego/pkg/resources/client"
+	"github.com/richseviora/huego/pkg/resources/light"
+	"github.com/richseviora/huego/pkg/resources/room"
+	"github.com/richseviora/huego/pkg/resources/scene"
+	"github.com/richseviora/huego/pkg/resources/zone"
+	"strings"
+)
+
+// resolver maps group and scene names to IDs, including resources created while applying a plan.
+type resolver struct {
+	groups map[string]string
+	scenes map[string]string
+}
+
+func groupKey(groupType, name string) string {
+	return groupType + "/" + name
+}
+
+func sceneKey(groupType, groupName, name string) string {
+	return groupType + "/" + groupName + "/" + name
+}
+
+func (r *resolver) group(groupType, name string) (string, error) {
+	id, ok := r.groups[groupKey(groupType, name)]
+	if !ok {
+		return "", fmt.Errorf("%s %q has not been created", groupType, name)
+	}
+	return id, nil
+}
+
+func (r *resolver) scene(groupType, groupName, name string) (string, error) {
+	id, ok := r.scenes[sceneKey(groupType, groupName, name)]
+	if !ok {
+		return "", fmt.Errorf("scene %q in %s %q has not been created", name, groupType, groupName)
+	}
+	return id, nil
+}
+
+type planner struct {
+	cfg      *Config
+	state    *State
+	resolver *resolver
+	errs     []error
+
+	deviceNames    map[string]string
+	devicesByName  map[string][]string
+	lightsByDevice map[string][]string
+	lightIDs       map[string]string
+	lightNames     map[string]string
+	lightModels    map[string]string
+	groupNames     map[string]string
+	sceneNames     map[string]string
+
+	// groupLights holds the desired lights of every room and zone keyed by groupKey.
+	groupLights map[string][]string
+
+	behaviorDeletes []Change
+	sceneDeletes    []Change
+	zoneDeletes     []Change
+	roomDeletes     []Change
+	rooms           []Change
+	zones           []Change
+	lights          []Change
+	scenes          []Change
+	behaviors       []Change
+}
+
+func newPlanner(cfg *Config, state *State) *planner {
+	p := &planner{
+		cfg:   cfg,
+		state: state,
+		resolver: &resolver{
+			groups: make(map[string]string),
+			scenes: make(map[string]string),
+		},
+		deviceNames:    make(map[string]string),
+		devicesByName:  make(map[string][]string),
+		lightsByDevice: make(map[string][]string),
+		lightIDs:       make(map[string]string),
+		lightNames:     make(map[string]string),
+		lightModels:    make(map[string]string),
+		groupNames:     make(map[string]string),
+		sceneNames:     make(map[string]string),
+		groupLights:    make(map[string][]string),
+	}
+	models := make(map[string]string)
+	for _, d := range state.Devices {
+		p.deviceNames[d.ID] = d.Metadata.Name
+		p.devicesByName[d.Metadata.Name] = append(p.devicesByName[d.Metadata.Name], d.ID)
+		models[d.ID] = d.ProductData.ModelID
+		for _, service := range d.Services {
+			if service.Rtype == "light" {
+				p.lightsByDevice[d.ID] = append(p.lightsByDevice[d.ID], service.Rid)
+			}
+		}
+	}
+	for _, l := range state.Lights {
+		p.lightNames[l.ID] = l.Metadata.Name
+		p.lightModels[l.ID] = models[l.Owner.RID]
+	}
+	for _, r := range state.Rooms {
+		key := groupKey("room", r.Metadata.Name)
+		p.resolver.groups[key] = r.ID
+		p.groupNames[r.ID] = key
+		p.groupLights[key] = nil
+		for _, child := range r.Children {
+			if child.RType == "device" {
+				p.groupLights[key] = append(p.groupLights[key], p.lightsByDevice[child.RID]...)
+			}
+		}
+	}
+	for _, z := range state.Zones {
+		key := groupKey("zone", z.Metadata.Name)
+		p.resolver.groups[key] = z.ID
+		p.groupNames[z.ID] = key
+		p.groupLights[key] = nil
+		for _, child := range z.Children {
+			switch child.RType {
+			case "light":
+				p.groupLights[key] = append(p.groupLights[key], child.RID)
+			case "device":
+				p.groupLights[key] = append(p.groupLights[key], p.lightsByDevice[child.RID]...)
+			}
+		}
+	}
+	for _, s := range state.Scenes {
+		if group, ok := p.groupNames[s.Group.RID]; ok {
+			p.resolver.scenes[group+"/"+s.Metadata.Name] = s.ID
+			p.sceneNames[s.ID] = s.Metadata.Name
+		}
+	}
+	return p
+}
+
+// device resolves a device ID or name. IDs take precedence, and a name shared by several devices is an error rather
+// than a guess, as the wrong device could be moved.
+func (p *planner) device(ref string) (string, error) {
+	if _, ok := p.deviceNames[ref]; ok {
+		return ref, nil
+	}
+	ids := p.devicesByName[ref]
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("device %q not found", ref)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("%d devices are named %q, use one of their IDs (%s) or rename them", len(ids), ref, strings.Join(ids, ", "))
+	}
+}
+
+func (p *planner) errorf(format string, args ...interface{}) {
+	p.errs = append(p.errs, fmt.Errorf(format, args...))
+}
+
+// planLights renames lights first so that rooms, zones and scenes can refer to the new names.
+func (p *planner) planLights() {
+	for _, l := range p.state.Lights {
+		p.lightIDs[l.ID] = l.ID
+	}
+	renamed := make(map[string]bool)
+	for _, cl := range p.cfg.Lights {
+		var existing *light.Light
+		for i := range p.state.Lights {
+			l := &p.state.Lights[i]
+			if (cl.ID != "" && l.ID == cl.ID) || (cl.ID == "" && l.Metadata.Name == cl.Name) {
+				existing = l
+				break
+			}
+		}
+		if existing == nil {
+			p.errorf("light %q not found", cl.Name)
+			continue
+		}
+		p.lightNames[existing.ID] = cl.Name
+		renamed[existing.ID] = true
+		if existing.Metadata.Name == cl.Name {
+			continue
+		}
+		id, name := existing.ID, cl.Name
+		p.lights = append(p.lights, Change{
+			Action:       Update,
+			ResourceType: "light",
+			ID:           id,
+			Name:         name,
+			Diffs:        diffString(nil, "name", existing.Metadata.Name, name),
+			apply: func(ctx context.Context, c client.HueServiceClient, r *resolver) error {
+				return c.LightService().UpdateLight(ctx, light.LightUpdate{
+					ID:       id,
+					Metadata: &light.LightMetadataUpdate{Name: &name},
+				})
+			},
+		})
+	}
+	for _, l := range p.state.Lights {
+		name := p.lightNames[l.ID]
+		if existing, taken := p.lightIDs[name]; taken && existing != l.ID && !renamed[l.ID] {
+			continue
+		}
+		p.lightIDs[name] = l.ID
+	}
+}
+
+func (p *planner) findRoom(id, name string) *room.RoomData {
+	for i := range p.state.Rooms {
+		r := &p.state.Rooms[i]
+		if (id != "" && r.ID == id) || (id == "" && r.Metadata.Name == name) {
+			return r
+		}
+	}
+	return nil
+}
+
+func (p *planner) findZone(id, name string) *zone.ZoneData {
+	for i := range p.state.Zones {
+		z := &p.state.Zones[i]
+		if (id != "" && z.ID == id) || (id == "" && z.Metadata.Name == name) {
+			return z
+		}
+	}
+	return nil
+}
+
+func (p *planner) findScene(id, groupID, name string) *scene.SceneData {
+	for i := range p.state.Scenes {
+		s := &p.state.Scenes[i]
+		if (id != "" && s.ID == id) || (id == "" && groupID != "" && s.Group.RID == groupID && s.Metadata.Name == name) {
+			return s
+		}
+	}
+	return nil
+}
+
+func (p *planner) lightNameList(ids []string) []string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = p.lightNames[id]
+	}
+	return names
+}
+
+// sceneInventory describes every group using groupKey as its ID so that scenes can be remapped before new groups
+// have been created.
+func (p *planner) sceneInventory() *portable.Inventory {
+	var groups []portable.InventoryGroup
+	for key, lightIDs := range p.groupLights {
+		groupType, name, _ := strings.Cut(key, "/")
+		groups = append(groups, portable.InventoryGroup{ID: key, Type: groupType, Name: name, LightIDs: lightIDs})
+	}
+	lights := make([]portable.InventoryLight, 0, len(p.lightNames))
+	for id, name := range p.lightNames {
+		lights = append(lights, portable.InventoryLight{ID: id, Name: name, ModelID: p.lightModels[id]})
+	}
+	return portable.NewInventory(groups, lights)
+}
@@ -0,0 +1,140 @@
+package homeconfig
+
+import (
+	"context"
+	"github.com/richseviora/huego/pkg/portable"
+	"github.com/richseviora/huego/pkg/resources/client"
+	"github.com/richseviora/huego/pkg/resources/scene"
+)
+
+func (p *planner) planScenes() {
+	inventory := p.sceneInventory()
+	managed := make(map[string]bool)
+	for _, cs := range p.cfg.Scenes {
+		plan, err := inventory.Remap(&portable.SceneExport{Version: portable.FormatVersion, Scenes: []portable.Scene{cs.Scene}})
+		if err != nil {
+			p.errs = append(p.errs, err)
+			continue
+		}
+		for _, u := range plan.Unmatched {
+			if u.Ambiguous {
+				p.errorf("scene %q: several lights named %q in %s %q, pin them with model IDs or rename them", u.Scene, u.Light.Name, cs.Group.Type, cs.Group.Name)
+				continue
+			}
+			p.errorf("scene %q: light %q not found in %s %q", u.Scene, u.Light.Name, cs.Group.Type, cs.Group.Name)
+		}
+		desired := plan.Scenes[0]
+		groupType, groupName := cs.Group.Type, cs.Group.Name
+		existing := p.findScene(cs.ID, p.resolver.groups[groupKey(groupType, groupName)], cs.Name)
+		if cs.ID != "" && existing == nil {
+			p.errorf("scene %q with ID %s not found", cs.Name, cs.ID)
+			continue
+		}
+
+		if existing == nil {
+			var diffs []FieldDiff
+			diffs = diffString(diffs, "group", "", groupKey(groupType, groupName))
+			diffs = p.diffSceneActions(diffs, nil, desired.Actions)
+			name := cs.Name
+			p.scenes = append(p.scenes, Change{
+				Action:       Create,
+				ResourceType: "scene",
+				Name:         name,
+				Diffs:        diffs,
+				apply: func(ctx context.Context, c client.HueServiceClient, r *resolver) error {
+					groupID, err := r.group(groupType, groupName)
+					if err != nil {
+						return err
+					}
+					create := desired
+					create.Group.RID = groupID
+					ref, err := c.SceneService().CreateScene(ctx, create)
+					if err != nil {
+						return err
+					}
+					r.scenes[sceneKey(groupType, groupName, name)] = ref.RID
+					return nil
+				},
+			})
+			continue
+		}
+
+		managed[existing.ID] = true
+		p.resolver.scenes[sceneKey(groupType, groupName, cs.Name)] = existing.ID
+		var diffs []FieldDiff
+		diffs = diffString(diffs, "name", existing.Metadata.Name, cs.Name)
+		diffs = p.diffSceneActions(diffs, existing.Actions, desired.Actions)
+		var existingPalette *scene.Palette
+		if existing.Palette.IsDynamic() {
+			existingPalette = &existing.Palette
+		}
+		diffs = diffJSON(diffs, "palette", existingPalette, desired.Palette)
+		// Speed and auto_dynamic are only managed when the configuration sets them.
+		if cs.Speed != nil {
+			diffs = diffJSON(diffs, "speed", existing.Speed, *cs.Speed)
+		}
+		if cs.AutoDynamic != nil {
+			diffs = diffJSON(diffs, "auto_dynamic", existing.AutoDynamic, *cs.AutoDynamic)
+		}
+		if len(diffs) == 0 {
+			continue
+		}
+		id := existing.ID
+		update := scene.SceneUpdate{
+			Metadata:    &scene.SceneMetadata{Name: cs.Name, Image: existing.Metadata.Image},
+			Actions:     desired.Actions,
+			Palette:     desired.Palette,
+			Speed:       desired.Speed,
+			AutoDynamic: desired.AutoDynamic,
+		}
+		p.scenes = append(p.scenes, Change{
+			Action:       Update,
+			ResourceType: "scene",
+			ID:           id,
+			Name:         cs.Name,
+			Diffs:        diffs,
+			apply: func(ctx context.Context, c client.HueServiceClient, _ *resolver) error {
+				_, err := c.SceneService().UpdateScene(ctx, id, update)
+				return err
+			},
+		})
+	}
+
+	if !p.cfg.Prune {
+		return
+	}
+	for _, s := range p.state.Scenes {
+		if managed[s.ID] {
+			continue
+		}
+		id := s.ID
+		p.sceneDeletes = append(p.sceneDeletes, Change{
+			Action:       Delete,
+			ResourceType: "scene",
+			ID:           id,
+			Name:         s.Metadata.Name,
+			apply: func(ctx context.Context, c client.HueServiceClient, _ *resolver) error {
+				return c.SceneService().DeleteScene(ctx, id)
+			},
+		})
+	}
+}
+
+// diffSceneActions reports a diff per light whose action is added, removed or changed.
+func (p *planner) diffSceneActions(diffs []FieldDiff, current, desired []scene.ActionTarget) []FieldDiff {
+	currentByLight := make(map[string]string, len(current))
+	for _, a := range current {
+		currentByLight[a.Target.Rid] = toJSON(a.Action)
+	}
+	desiredLights := make(map[string]bool, len(desired))
+	for _, a := range desired {
+		desiredLights[a.Target.Rid] = true
+		diffs = diffString(diffs, "actions["+p.lightNames[a.Target.Rid]+"]", currentByLight[a.Target.Rid], toJSON(a.Action))
+	}
+	for _, a := range current {
+		if !desiredLights[a.Target.Rid] {
+			diffs = diffString(diffs, "actions["+p.lightNames[a.Target.Rid]+"]", currentByLight[a.Target.Rid], "")
+		}
+	}
+	return diffs
+}
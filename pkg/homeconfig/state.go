@@ -0,0 +1,65 @@
+package homeconfig
+
+import (
+	"context"
+	"github.com/richseviora/huego/pkg/resources/behavior_instance"
+	"github.com/richseviora/huego/pkg/resources/behavior_script"
+	"github.com/richseviora/huego/pkg/resources/client"
+	"github.com/richseviora/huego/pkg/resources/device"
+	"github.com/richseviora/huego/pkg/resources/light"
+	"github.com/richseviora/huego/pkg/resources/room"
+	"github.com/richseviora/huego/pkg/resources/scene"
+	"github.com/richseviora/huego/pkg/resources/zone"
+)
+
+// State is the live bridge data a plan is computed against.
+type State struct {
+	Devices   []device.Data            `json:"devices"`
+	Lights    []light.Light            `json:"lights"`
+	Rooms     []room.RoomData          `json:"rooms"`
+	Zones     []zone.ZoneData          `json:"zones"`
+	Scenes    []scene.SceneData        `json:"scenes"`
+	Behaviors []behavior_instance.Data `json:"behaviors"`
+	Scripts   []behavior_script.Data   `json:"scripts"`
+}
+
+// LoadState reads every resource type the configuration manages.
+func LoadState(ctx context.Context, c client.HueServiceClient) (*State, error) {
+	devices, err := c.DeviceService().GetAllDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lights, err := c.LightService().GetAllLights(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rooms, err := c.RoomService().GetAllRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	zones, err := c.ZoneService().GetAllZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scenes, err := c.SceneService().GetAllScenes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	behaviors, err := c.BehaviorInstanceService().GetAllBehaviorInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scripts, err := c.BehaviorScriptService().GetAllBehaviorScripts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &State{
+		Devices:   devices.Data,
+		Lights:    lights.Data,
+		Rooms:     rooms.Data,
+		Zones:     zones.Data,
+		Scenes:    scenes.Data,
+		Behaviors: behaviors.Data,
+		Scripts:   scripts.Data,
+	}, nil
+}
@@ -0,0 +1,35 @@
+package meshhealth
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// History carries state between runs so a report can say how long a device has been unhealthy.
+type History struct {
+	// UnhealthySince maps device IDs to the first time they were seen unhealthy.
+	UnhealthySince map[string]time.Time `json:"unhealthy_since"`
+}
+
+func NewHistory() *History {
+	return &History{UnhealthySince: make(map[string]time.Time)}
+}
+
+// ReadHistory reads a history written by Write.
+func ReadHistory(r io.Reader) (*History, error) {
+	result := NewHistory()
+	if err := json.NewDecoder(r).Decode(result); err != nil {
+		return nil, err
+	}
+	if result.UnhealthySince == nil {
+		result.UnhealthySince = make(map[string]time.Time)
+	}
+	return result, nil
+}
+
+func (h *History) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(h)
+}
@@ -0,0 +1,179 @@
+package meshhealth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/richseviora/huego/pkg/resources/bridge"
+	"github.com/richseviora/huego/pkg/resources/client"
+	"github.com/richseviora/huego/pkg/resources/device"
+	"github.com/richseviora/huego/pkg/resources/room"
+	"github.com/richseviora/huego/pkg/resources/zigbee_connectivity"
+	"slices"
+	"sort"
+	"time"
+)
+
+// ErrBridgeConnectivityNotFound is returned when no zigbee_connectivity is owned by the bridge device.
+var ErrBridgeConnectivityNotFound = errors.New("bridge zigbee_connectivity not found")
+
+// DeviceHealth is the connectivity of one unhealthy device.
+type DeviceHealth struct {
+	DeviceID   string
+	Name       string
+	ModelID    string
+	RoomID     string
+	RoomName   string
+	MacAddress string
+	Status     string
+	// UnhealthySince is the time of the first report, in this or an earlier run, that found the device unhealthy.
+	UnhealthySince time.Time
+	UnhealthyFor   time.Duration
+}
+
+type Report struct {
+	Taken time.Time
+	// BridgeConnectivityID is the bridge's zigbee_connectivity, used to change the channel.
+	BridgeConnectivityID string
+	Channel              string
+	ChannelStatus        string
+	ExtendedPanID        string
+	Healthy              int
+	// Unhealthy is sorted with the longest unhealthy devices first.
+	Unhealthy []DeviceHealth
+}
+
+// Input holds the resources a report is built from.
+type Input struct {
+	// Bridge identifies the bridge device, by its owner, so it is reported separately from the devices it connects.
+	Bridge       *bridge.Data
+	Devices      []device.Data
+	Rooms        []room.RoomData
+	Connectivity []zigbee_connectivity.Data
+}
+
+// Load reads the resources a report needs from the bridge.
+func Load(ctx context.Context, c client.HueServiceClient) (*Input, error) {
+	devices, err := c.DeviceService().GetAllDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rooms, err := c.RoomService().GetAllRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	connectivity, err := c.ZigbeeConnectivityService().GetAllZigbeeConnectivity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.BridgeService().GetBridge(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Input{Bridge: b, Devices: devices.Data, Rooms: rooms.Data, Connectivity: connectivity.Data}, nil
+}
+
+// Check loads the resources from the bridge and builds a report, updating history.
+func Check(ctx context.Context, c client.HueServiceClient, history *History) (*Report, error) {
+	input, err := Load(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	return Build(time.Now(), input, history), nil
+}
+
+// Build creates a report as of now. Devices that are unhealthy keep the time they were first seen unhealthy in history,
+// and devices that recovered are removed from it. history may be nil when durations are not tracked.
+func Build(now time.Time, input *Input, history *History) *Report {
+	devices := make(map[string]device.Data)
+	for _, d := range input.Devices {
+		devices[d.ID] = d
+	}
+	roomOf := make(map[string]room.RoomData)
+	for _, r := range input.Rooms {
+		for _, child := range r.Children {
+			roomOf[child.RID] = r
+		}
+	}
+
+	var bridgeDeviceID string
+	if input.Bridge != nil {
+		bridgeDeviceID = input.Bridge.Owner.RID
+	}
+	if history != nil && history.UnhealthySince == nil {
+		history.UnhealthySince = make(map[string]time.Time)
+	}
+
+	result := &Report{Taken: now}
+	unhealthy := make(map[string]bool)
+	for _, zc := range input.Connectivity {
+		d := devices[zc.Owner.RID]
+		if bridgeDeviceID != "" && zc.Owner.RID == bridgeDeviceID {
+			result.BridgeConnectivityID = zc.ID
+			result.Channel = zc.Channel.Value
+			result.ChannelStatus = zc.Channel.Status
+			result.ExtendedPanID = zc.ExtendedPanID
+			continue
+		}
+		if zc.IsHealthy() {
+			result.Healthy++
+			continue
+		}
+		unhealthy[zc.Owner.RID] = true
+		entry := DeviceHealth{
+			DeviceID:       zc.Owner.RID,
+			Name:           d.Metadata.Name,
+			ModelID:        d.ProductData.ModelID,
+			MacAddress:     zc.MacAddress,
+			Status:         zc.Status,
+			UnhealthySince: now,
+		}
+		if r, ok := roomOf[zc.Owner.RID]; ok {
+			entry.RoomID = r.ID
+			entry.RoomName = r.Metadata.Name
+		}
+		if history != nil {
+			if since, ok := history.UnhealthySince[entry.DeviceID]; ok && since.Before(now) {
+				entry.UnhealthySince = since
+			}
+			history.UnhealthySince[entry.DeviceID] = entry.UnhealthySince
+		}
+		entry.UnhealthyFor = now.Sub(entry.UnhealthySince)
+		result.Unhealthy = append(result.Unhealthy, entry)
+	}
+	if history != nil {
+		for id := range history.UnhealthySince {
+			if !unhealthy[id] {
+				delete(history.UnhealthySince, id)
+			}
+		}
+	}
+	sort.Slice(result.Unhealthy, func(i, j int) bool {
+		a, b := result.Unhealthy[i], result.Unhealthy[j]
+		if !a.UnhealthySince.Equal(b.UnhealthySince) {
+			return a.UnhealthySince.Before(b.UnhealthySince)
+		}
+		return a.Name < b.Name
+	})
+	return result
+}
+
+// ChangeChannel moves the bridge's Zigbee network to another channel, one of zigbee_connectivity.Channels. Devices
+// follow over the next few minutes and are reported unhealthy meanwhile.
+func ChangeChannel(ctx context.Context, c client.HueServiceClient, channel string) error {
+	if !slices.Contains(zigbee_connectivity.Channels, channel) {
+		return fmt.Errorf("invalid channel %q, must be one of %v", channel, zigbee_connectivity.Channels)
+	}
+	input, err := Load(ctx, c)
+	if err != nil {
+		return err
+	}
+	report := Build(time.Now(), input, nil)
+	if report.BridgeConnectivityID == "" {
+		return ErrBridgeConnectivityNotFound
+	}
+	_, err = c.ZigbeeConnectivityService().UpdateZigbeeConnectivity(ctx, report.BridgeConnectivityID, zigbee_connectivity.UpdateRequest{
+		Channel: &zigbee_connectivity.ChannelUpdate{Value: channel},
+	})
+	return err
+}
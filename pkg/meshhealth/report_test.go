@@ -0,0 +1,104 @@
+package meshhealth
+
+import (
+	"bytes"
+	"github.com/google/go-cmp/cmp"
+	"github.com/richseviora/huego/pkg/resources/bridge"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/device"
+	"github.com/richseviora/huego/pkg/resources/room"
+	"github.com/richseviora/huego/pkg/resources/zigbee_connectivity"
+	"testing"
+	"time"
+)
+
+func testInput(statuses map[string]string) *Input {
+	input := &Input{
+		// The archetype differs from bridge_v2 to check the bridge is found through the bridge resource.
+		Bridge: &bridge.Data{ID: "bridge-resource", Owner: common.Reference{RID: "bridge", RType: "device"}},
+		Devices: []device.Data{
+			{ID: "bridge", ProductData: device.ProductData{ProductArchetype: device.UnknownArchetype}},
+			{ID: "d1", Metadata: device.Metadata{Name: "Hall"}, ProductData: device.ProductData{ModelID: "LCA001"}},
+			{ID: "d2", Metadata: device.Metadata{Name: "Porch"}},
+			{ID: "d3", Metadata: device.Metadata{Name: "Lamp"}},
+		},
+		Rooms: []room.RoomData{
+			{ID: "r1", Metadata: room.RoomMetadata{Name: "Hallway"}, Children: []common.Reference{{RID: "d1", RType: "device"}}},
+		},
+		Connectivity: []zigbee_connectivity.Data{{
+			ID:            "zb",
+			Owner:         common.Reference{RID: "bridge", RType: "device"},
+			Status:        zigbee_connectivity.StatusConnected,
+			Channel:       zigbee_connectivity.Channel{Status: "set", Value: zigbee_connectivity.Channel25},
+			ExtendedPanID: "abc",
+		}},
+	}
+	for _, id := range []string{"d1", "d2", "d3"} {
+		input.Connectivity = append(input.Connectivity, zigbee_connectivity.Data{
+			ID:     "zc-" + id,
+			Owner:  common.Reference{RID: id, RType: "device"},
+			Status: statuses[id],
+		})
+	}
+	return input
+}
+
+func TestBuild_TracksDurationAcrossRuns(t *testing.T) {
+	history := NewHistory()
+	first := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	Build(first, testInput(map[string]string{
+		"d1": zigbee_connectivity.StatusConnectivityIssue,
+		"d2": zigbee_connectivity.StatusDisconnected,
+		"d3": zigbee_connectivity.StatusConnected,
+	}), history)
+
+	// Round trip the history as a scheduled job would between runs.
+	var buf bytes.Buffer
+	if err := history.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	history, err := ReadHistory(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second := first.Add(2 * time.Hour)
+	report := Build(second, testInput(map[string]string{
+		"d1": zigbee_connectivity.StatusConnectivityIssue,
+		"d2": zigbee_connectivity.StatusConnected,
+		"d3": zigbee_connectivity.StatusUnidirectionalIncoming,
+	}), history)
+	expected := &Report{
+		Taken:                second,
+		BridgeConnectivityID: "zb",
+		Channel:              zigbee_connectivity.Channel25,
+		ChannelStatus:        "set",
+		ExtendedPanID:        "abc",
+		Healthy:              1,
+		Unhealthy: []DeviceHealth{
+			{
+				DeviceID: "d1", Name: "Hall", ModelID: "LCA001", RoomID: "r1", RoomName: "Hallway",
+				Status: zigbee_connectivity.StatusConnectivityIssue, UnhealthySince: first, UnhealthyFor: 2 * time.Hour,
+			},
+			{DeviceID: "d3", Name: "Lamp", Status: zigbee_connectivity.StatusUnidirectionalIncoming, UnhealthySince: second},
+		},
+	}
+	if diff := cmp.Diff(expected, report); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+	if _, ok := history.UnhealthySince["d2"]; ok {
+		t.Errorf("recovered device d2 is still in history")
+	}
+}
+
+func TestBuild_EmptyHistory(t *testing.T) {
+	history := &History{}
+	report := Build(time.Now(), testInput(map[string]string{
+		"d1": zigbee_connectivity.StatusDisconnected,
+		"d2": zigbee_connectivity.StatusConnected,
+		"d3": zigbee_connectivity.StatusConnected,
+	}), history)
+	if len(report.Unhealthy) != 1 || len(history.UnhealthySince) != 1 {
+		t.Errorf("Build() reported %d unhealthy devices and tracked %d, want 1", len(report.Unhealthy), len(history.UnhealthySince))
+	}
+}
@@ -0,0 +1,75 @@
+package onboarding
+
+import (
+	"context"
+	"fmt"
+	"github.com/richseviora/huego/pkg/resources/client"
+	"github.com/richseviora/huego/pkg/resources/device"
+	"github.com/richseviora/huego/pkg/resources/light"
+	"github.com/richseviora/huego/pkg/rooms"
+)
+
+// Step is one action run against a newly joined device.
+type Step struct {
+	Name string
+	Run  func(ctx context.Context, c client.HueServiceClient, d device.Data) error
+}
+
+// Rename names the device, and its light if it has one, with the name returned by name. An empty name skips the
+// device.
+func Rename(name func(device.Data) string) Step {
+	return Step{
+		Name: "rename",
+		Run: func(ctx context.Context, c client.HueServiceClient, d device.Data) error {
+			n := name(d)
+			if n == "" {
+				return nil
+			}
+			if _, err := c.DeviceService().RenameDevice(ctx, d.ID, n); err != nil {
+				return err
+			}
+			for _, id := range d.GetServiceIDs("light") {
+				if err := c.LightService().UpdateLight(ctx, light.LightUpdate{ID: id, Metadata: &light.LightMetadataUpdate{Name: &n}}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// AssignRoom moves the device into the room.
+func AssignRoom(roomID string) Step {
+	return Step{
+		Name: "assign room",
+		Run: func(ctx context.Context, c client.HueServiceClient, d device.Data) error {
+			_, err := rooms.MoveDevice(ctx, c, d.ID, roomID)
+			return err
+		},
+	}
+}
+
+// SetPowerUp applies a power-up preset such as light.PowerUpLastOnState to every light of the device.
+func SetPowerUp(preset string) Step {
+	return Step{
+		Name: "set power-up",
+		Run: func(ctx context.Context, c client.HueServiceClient, d device.Data) error {
+			for _, id := range d.GetServiceIDs("light") {
+				if err := c.LightService().UpdateLight(ctx, light.LightUpdate{ID: id, PowerUp: &light.PowerUpUpdate{Preset: preset}}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// RunPipeline runs the steps in order against the device, stopping at the first that fails.
+func RunPipeline(ctx context.Context, c client.HueServiceClient, d device.Data, steps ...Step) error {
+	for _, step := range steps {
+		if err := step.Run(ctx, c, d); err != nil {
+			return fmt.Errorf("device %s: %s: %w", d.ID, step.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/richseviora/huego/pkg/resources/client"
+	"github.com/richseviora/huego/pkg/resources/device"
+	"github.com/richseviora/huego/pkg/resources/zigbee_device_discovery"
+	"time"
+)
+
+// DefaultPollInterval is how often Search checks for joined devices.
+const DefaultPollInterval = 2 * time.Second
+
+// ErrNoDiscovery is returned when the bridge has no zigbee_device_discovery resource.
+var ErrNoDiscovery = errors.New("bridge does not support zigbee device discovery")
+
+type searchOptions struct {
+	serials      []string
+	installCodes []string
+	pollInterval time.Duration
+	onJoined     func(device.Data)
+}
+
+// SearchOption defines functional options for Search.
+type SearchOption func(*searchOptions)
+
+// WithSerials searches for lights by the serial number printed on them, which finds lights that were reset or still
+// belong to another bridge. The bridge accepts at most zigbee_device_discovery.MaxSearchCodes serials.
+func WithSerials(serials ...string) SearchOption {
+	return func(o *searchOptions) {
+		o.serials = append(o.serials, serials...)
+	}
+}
+
+// WithInstallCodes adds install codes for devices that need one to join.
+func WithInstallCodes(codes ...string) SearchOption {
+	return func(o *searchOptions) {
+		o.installCodes = append(o.installCodes, codes...)
+	}
+}
+
+// WithPollInterval overrides DefaultPollInterval.
+func WithPollInterval(interval time.Duration) SearchOption {
+	return func(o *searchOptions) {
+		o.pollInterval = interval
+	}
+}
+
+// WithJoinedCallback calls f for every device as soon as it is seen, while the search continues.
+func WithJoinedCallback(f func(device.Data)) SearchOption {
+	return func(o *searchOptions) {
+		o.onJoined = f
+	}
+}
+
+// Search starts a search for new Zigbee devices and waits until the bridge finishes it or ctx is done. It returns the
+// devices that joined during the search, even when ctx ends it early.
+func Search(ctx context.Context, c client.HueServiceClient, opts ...SearchOption) ([]device.Data, error) {
+	options := searchOptions{pollInterval: DefaultPollInterval}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if len(options.serials) > zigbee_device_discovery.MaxSearchCodes {
+		return nil, fmt.Errorf("at most %d serials can be searched at once, got %d", zigbee_device_discovery.MaxSearchCodes, len(options.serials))
+	}
+	discoveries, err := c.ZigbeeDeviceDiscoveryService().GetAllZigbeeDeviceDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(discoveries.Data) == 0 {
+		return nil, ErrNoDiscovery
+	}
+	discoveryID := discoveries.Data[0].ID
+
+	known := make(map[string]bool)
+	devices, err := c.DeviceService().GetAllDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range devices.Data {
+		known[d.ID] = true
+	}
+
+	_, err = c.ZigbeeDeviceDiscoveryService().UpdateZigbeeDeviceDiscovery(ctx, discoveryID, zigbee_device_discovery.UpdateRequest{
+		Action: zigbee_device_discovery.SearchAction{
+			ActionType:   zigbee_device_discovery.ActionSearch,
+			SearchCodes:  options.serials,
+			InstallCodes: options.installCodes,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start search: %w", err)
+	}
+
+	var joined []device.Data
+	ticker := time.NewTicker(options.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return joined, ctx.Err()
+		case <-ticker.C:
+		}
+		devices, err := c.DeviceService().GetAllDevices(ctx)
+		if err != nil {
+			return joined, err
+		}
+		for _, d := range devices.Data {
+			if known[d.ID] {
+				continue
+			}
+			known[d.ID] = true
+			joined = append(joined, d)
+			if options.onJoined != nil {
+				options.onJoined(d)
+			}
+		}
+		status, err := c.ZigbeeDeviceDiscoveryService().GetZigbeeDeviceDiscovery(ctx, discoveryID)
+		if err != nil {
+			return joined, err
+		}
+		if status.Status != zigbee_device_discovery.Active {
+			return joined, nil
+		}
+	}
+}
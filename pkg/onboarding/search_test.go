@@ -0,0 +1,135 @@
+package onboarding
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	client2 "github.com/richseviora/huego/internal/client"
+	"github.com/richseviora/huego/pkg/logger"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/device"
+	"github.com/richseviora/huego/pkg/resources/light"
+	"github.com/richseviora/huego/pkg/resources/zigbee_device_discovery"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBridge lets one new device join on each device poll after the search starts, then ends the search.
+type fakeBridge struct {
+	mu       sync.Mutex
+	devices  []device.Data
+	pending  []device.Data
+	search   *zigbee_device_discovery.UpdateRequest
+	updates  map[string][]json.RawMessage
+	searched bool
+}
+
+func (f *fakeBridge) handler() http.Handler {
+	mux := http.NewServeMux()
+	write := func(w http.ResponseWriter, data interface{}) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{}, "data": data})
+	}
+	mux.HandleFunc("GET /clip/v2/resource/device", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if f.searched && len(f.pending) > 0 {
+			f.devices = append(f.devices, f.pending[0])
+			f.pending = f.pending[1:]
+		}
+		write(w, f.devices)
+	})
+	mux.HandleFunc("GET /clip/v2/resource/zigbee_device_discovery", func(w http.ResponseWriter, r *http.Request) {
+		write(w, []zigbee_device_discovery.Data{{ID: "zdd", Status: zigbee_device_discovery.Ready}})
+	})
+	mux.HandleFunc("GET /clip/v2/resource/zigbee_device_discovery/zdd", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		status := zigbee_device_discovery.Active
+		if len(f.pending) == 0 {
+			status = zigbee_device_discovery.Ready
+		}
+		write(w, []zigbee_device_discovery.Data{{ID: "zdd", Status: status}})
+	})
+	mux.HandleFunc("PUT /clip/v2/resource/zigbee_device_discovery/zdd", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.search = &zigbee_device_discovery.UpdateRequest{}
+		_ = json.NewDecoder(r.Body).Decode(f.search)
+		f.searched = true
+		write(w, []common.Reference{{RID: "zdd", RType: "zigbee_device_discovery"}})
+	})
+	mux.HandleFunc("PUT /clip/v2/resource/{rtype}/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		var body json.RawMessage
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.updates[r.PathValue("id")] = append(f.updates[r.PathValue("id")], body)
+		write(w, []common.Reference{{RID: r.PathValue("id"), RType: r.PathValue("rtype")}})
+	})
+	return mux
+}
+
+func TestSearch(t *testing.T) {
+	bridge := &fakeBridge{
+		devices: []device.Data{{ID: "bridge"}},
+		pending: []device.Data{
+			{ID: "d1", Services: []device.Services{{Rid: "l1", Rtype: "light"}}},
+			{ID: "d2", Services: []device.Services{{Rid: "l2", Rtype: "light"}}},
+		},
+		updates: make(map[string][]json.RawMessage),
+	}
+	server := httptest.NewServer(bridge.handler())
+	defer server.Close()
+	c := client2.NewAPIClient(server.URL, "key", logger.NoopLogger{})
+
+	var seen []string
+	joined, err := Search(context.Background(), c,
+		WithSerials("ABC123"),
+		WithPollInterval(time.Millisecond),
+		WithJoinedCallback(func(d device.Data) {
+			seen = append(seen, d.ID)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"d1", "d2"}, seen); diff != "" {
+		t.Errorf("joined mismatch (-want +got):\n%s", diff)
+	}
+	expectedSearch := &zigbee_device_discovery.UpdateRequest{Action: zigbee_device_discovery.SearchAction{
+		ActionType:  zigbee_device_discovery.ActionSearch,
+		SearchCodes: []string{"ABC123"},
+	}}
+	if diff := cmp.Diff(expectedSearch, bridge.search); diff != "" {
+		t.Errorf("search mismatch (-want +got):\n%s", diff)
+	}
+
+	names := map[string]string{"d1": "Hall 1", "d2": "Hall 2"}
+	for _, d := range joined {
+		err := RunPipeline(context.Background(), c, d,
+			Rename(func(d device.Data) string { return names[d.ID] }),
+			SetPowerUp(light.PowerUpLastOnState),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectedUpdates := map[string][]string{
+		"d1": {`{"metadata":{"name":"Hall 1"}}`},
+		"l1": {`{"metadata":{"name":"Hall 1"}}`, `{"powerup":{"preset":"last_on_state"}}`},
+		"d2": {`{"metadata":{"name":"Hall 2"}}`},
+		"l2": {`{"metadata":{"name":"Hall 2"}}`, `{"powerup":{"preset":"last_on_state"}}`},
+	}
+	updates := make(map[string][]string)
+	for id, bodies := range bridge.updates {
+		for _, b := range bodies {
+			updates[id] = append(updates[id], string(b))
+		}
+	}
+	if diff := cmp.Diff(expectedUpdates, updates); diff != "" {
+		t.Errorf("updates mismatch (-want +got):\n%s", diff)
+	}
+}
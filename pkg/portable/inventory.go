@@ -0,0 +1,159 @@
+package portable
+
+import (
+	"context"
+	"github.com/richseviora/huego/pkg/resources/client"
+)
+
+// InventoryGroup is a room or zone along with the lights it contains.
+type InventoryGroup struct {
+	ID       string
+	Type     string
+	Name     string
+	LightIDs []string
+}
+
+// InventoryLight is a light along with the model of its owning device.
+type InventoryLight struct {
+	ID      string
+	Name    string
+	ModelID string
+}
+
+// Inventory holds the names needed to translate bridge IDs to and from the portable format.
+type Inventory struct {
+	// groups keeps the order groups were given in, so lookups by name are deterministic.
+	groups     []InventoryGroup
+	groupsByID map[string]InventoryGroup
+	lightsByID map[string]InventoryLight
+}
+
+// NewInventory builds an inventory from groups and lights that have already been loaded.
+func NewInventory(groups []InventoryGroup, lights []InventoryLight) *Inventory {
+	inv := &Inventory{
+		groupsByID: make(map[string]InventoryGroup, len(groups)),
+		lightsByID: make(map[string]InventoryLight, len(lights)),
+	}
+	for _, g := range groups {
+		inv.groups = append(inv.groups, g)
+		inv.groupsByID[g.ID] = g
+	}
+	for _, l := range lights {
+		inv.lightsByID[l.ID] = l
+	}
+	return inv
+}
+
+// LoadInventory reads rooms, zones, devices and lights from the bridge.
+func LoadInventory(ctx context.Context, c client.HueServiceClient) (*Inventory, error) {
+	devices, err := c.DeviceService().GetAllDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lights, err := c.LightService().GetAllLights(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rooms, err := c.RoomService().GetAllRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	zones, err := c.ZoneService().GetAllZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	modelByDevice := make(map[string]string, len(devices.Data))
+	lightsByDevice := make(map[string][]string, len(devices.Data))
+	for _, d := range devices.Data {
+		modelByDevice[d.ID] = d.ProductData.ModelID
+		for _, service := range d.Services {
+			if service.Rtype == "light" {
+				lightsByDevice[d.ID] = append(lightsByDevice[d.ID], service.Rid)
+			}
+		}
+	}
+
+	inventoryLights := make([]InventoryLight, 0, len(lights.Data))
+	for _, l := range lights.Data {
+		inventoryLights = append(inventoryLights, InventoryLight{
+			ID:      l.ID,
+			Name:    l.Metadata.Name,
+			ModelID: modelByDevice[l.Owner.RID],
+		})
+	}
+
+	var groups []InventoryGroup
+	for _, r := range rooms.Data {
+		group := InventoryGroup{ID: r.ID, Type: "room", Name: r.Metadata.Name}
+		for _, child := range r.Children {
+			if child.RType == "device" {
+				group.LightIDs = append(group.LightIDs, lightsByDevice[child.RID]...)
+			}
+		}
+		groups = append(groups, group)
+	}
+	for _, z := range zones.Data {
+		group := InventoryGroup{ID: z.ID, Type: "zone", Name: z.Metadata.Name}
+		for _, child := range z.Children {
+			switch child.RType {
+			case "light":
+				group.LightIDs = append(group.LightIDs, child.RID)
+			case "device":
+				group.LightIDs = append(group.LightIDs, lightsByDevice[child.RID]...)
+			}
+		}
+		groups = append(groups, group)
+	}
+	return NewInventory(groups, inventoryLights), nil
+}
+
+// findGroup returns the groups of the type with the name, in inventory order.
+func (inv *Inventory) findGroup(ref GroupRef) []InventoryGroup {
+	var result []InventoryGroup
+	for _, g := range inv.groups {
+		if g.Type == ref.Type && g.Name == ref.Name {
+			result = append(result, g)
+		}
+	}
+	return result
+}
+
+type lightMatch int
+
+const (
+	lightFound lightMatch = iota
+	lightNotFound
+	lightAmbiguous
+)
+
+// findLight matches on name and model within the group, falling back to the name alone when the model differs.
+// Lights in used are skipped, so one light is never targeted twice by a scene. When several lights match equally
+// well, none is picked, as guessing would silently swap which lamp gets which action.
+func (inv *Inventory) findLight(group InventoryGroup, ref LightRef, used map[string]bool) (string, lightMatch) {
+	var modelMatches, nameMatches []string
+	for _, id := range group.LightIDs {
+		l, ok := inv.lightsByID[id]
+		if !ok || l.Name != ref.Name || used[id] {
+			continue
+		}
+		if ref.ModelID == "" || l.ModelID == ref.ModelID {
+			modelMatches = append(modelMatches, l.ID)
+		} else {
+			nameMatches = append(nameMatches, l.ID)
+		}
+	}
+	candidates := modelMatches
+	if len(candidates) == 0 {
+		candidates = nameMatches
+	}
+	switch len(candidates) {
+	case 0:
+		return "", lightNotFound
+	case 1:
+		used[candidates[0]] = true
+		return candidates[0], lightFound
+	default:
+		return "", lightAmbiguous
+	}
+}
@@ -0,0 +1,269 @@
+package portable
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/richseviora/huego/pkg/resources/client"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/scene"
+	"gopkg.in/yaml.v3"
+	"io"
+)
+
+// FormatVersion is written to every export and checked on read.
+const FormatVersion = 1
+
+// GroupRef identifies a room or zone by name rather than by ID.
+type GroupRef struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// LightRef identifies a light by its name and the model of the device it belongs to.
+type LightRef struct {
+	Name    string `json:"name"`
+	ModelID string `json:"model_id,omitempty"`
+}
+
+type Action struct {
+	Light  LightRef     `json:"light"`
+	Action scene.Action `json:"action"`
+}
+
+type Scene struct {
+	Name    string         `json:"name"`
+	Group   GroupRef       `json:"group"`
+	Actions []Action       `json:"actions"`
+	Palette *scene.Palette `json:"palette,omitempty"`
+	// Speed and AutoDynamic are left unchanged on import when omitted.
+	Speed       *float64 `json:"speed,omitempty"`
+	AutoDynamic *bool    `json:"auto_dynamic,omitempty"`
+}
+
+// SceneExport is the bridge independent representation of a set of scenes.
+type SceneExport struct {
+	Version int     `json:"version"`
+	Scenes  []Scene `json:"scenes"`
+}
+
+// Write encodes the export as indented JSON.
+func (e *SceneExport) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(e)
+}
+
+// WriteYAML encodes the export as YAML, using the same field names and order as Write.
+func (e *SceneExport) WriteYAML(w io.Writer) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	// JSON is valid YAML, so decoding it into a node keeps the field order, which a map would lose.
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return err
+	}
+	resetStyle(&document)
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// resetStyle drops the flow style and quoting of JSON so the node is written in block style.
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// ReadSceneExport decodes an export written by SceneExport.Write.
+func ReadSceneExport(r io.Reader) (*SceneExport, error) {
+	var result SceneExport
+	if err := json.NewDecoder(r).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported scene export version %d", result.Version)
+	}
+	return &result, nil
+}
+
+// ReadSceneExportYAML decodes an export written by SceneExport.WriteYAML.
+func ReadSceneExportYAML(r io.Reader) (*SceneExport, error) {
+	var document interface{}
+	if err := yaml.NewDecoder(r).Decode(&document); err != nil {
+		return nil, err
+	}
+	// The JSON form is canonical, so the version check and field names are shared with ReadSceneExport.
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	return ReadSceneExport(bytes.NewReader(data))
+}
+
+// ExportScenes converts the scenes with the given IDs into the portable format. All scenes are exported when no IDs
+// are supplied.
+func ExportScenes(ctx context.Context, c client.HueServiceClient, sceneIDs ...string) (*SceneExport, error) {
+	inventory, err := LoadInventory(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	scenes, err := c.SceneService().GetAllScenes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool, len(sceneIDs))
+	for _, id := range sceneIDs {
+		wanted[id] = true
+	}
+	result := &SceneExport{Version: FormatVersion}
+	for _, s := range scenes.Data {
+		if len(wanted) > 0 && !wanted[s.ID] {
+			continue
+		}
+		exported, err := inventory.ExportScene(s)
+		if err != nil {
+			return nil, err
+		}
+		result.Scenes = append(result.Scenes, *exported)
+		wanted[s.ID] = false
+	}
+	for _, id := range sceneIDs {
+		if wanted[id] {
+			return nil, fmt.Errorf("scene %s not found", id)
+		}
+	}
+	return result, nil
+}
+
+// ExportScene converts a single scene using the inventory's names.
+func (inv *Inventory) ExportScene(s scene.SceneData) (*Scene, error) {
+	group, ok := inv.groupsByID[s.Group.RID]
+	if !ok {
+		return nil, fmt.Errorf("scene %s references unknown group %s", s.ID, s.Group.RID)
+	}
+	result := &Scene{
+		Name:  s.Metadata.Name,
+		Group: GroupRef{Type: group.Type, Name: group.Name},
+	}
+	// Defaults are left out so importing does not override the target bridge's.
+	if s.Speed != 0 {
+		speed := s.Speed
+		result.Speed = &speed
+	}
+	if s.AutoDynamic {
+		autoDynamic := s.AutoDynamic
+		result.AutoDynamic = &autoDynamic
+	}
+	if s.Palette.IsDynamic() {
+		palette := s.Palette
+		result.Palette = &palette
+	}
+	for _, a := range s.Actions {
+		l, ok := inv.lightsByID[a.Target.Rid]
+		if !ok {
+			return nil, fmt.Errorf("scene %s references unknown light %s", s.ID, a.Target.Rid)
+		}
+		result.Actions = append(result.Actions, Action{
+			Light:  LightRef{Name: l.Name, ModelID: l.ModelID},
+			Action: a.Action,
+		})
+	}
+	return result, nil
+}
+
+// UnmatchedLight is reported when an exported light has no counterpart on the target bridge, or when Ambiguous, when
+// several lights in the group have the same name and model.
+type UnmatchedLight struct {
+	Scene     string
+	Light     LightRef
+	Ambiguous bool
+}
+
+// ImportPlan holds the scenes ready to be created on the target bridge.
+type ImportPlan struct {
+	Scenes    []scene.SceneCreate
+	Unmatched []UnmatchedLight
+}
+
+// Remap resolves the names in the export against the inventory of the target bridge. Scenes whose group cannot be
+// found, or is not unique, are returned as an error, lights that cannot be matched are dropped from the scene and
+// reported.
+func (inv *Inventory) Remap(export *SceneExport) (*ImportPlan, error) {
+	plan := &ImportPlan{}
+	for _, s := range export.Scenes {
+		groups := inv.findGroup(s.Group)
+		if len(groups) == 0 {
+			return nil, fmt.Errorf("scene %q: %s %q not found", s.Name, s.Group.Type, s.Group.Name)
+		}
+		if len(groups) > 1 {
+			return nil, fmt.Errorf("scene %q: %d %ss are named %q", s.Name, len(groups), s.Group.Type, s.Group.Name)
+		}
+		group := groups[0]
+		create := scene.SceneCreate{
+			Metadata: scene.SceneMetadata{Name: s.Name},
+			Group:    common.Reference{RID: group.ID, RType: group.Type},
+			Palette:  s.Palette,
+		}
+		if s.Speed != nil {
+			speed := *s.Speed
+			create.Speed = &speed
+		}
+		if s.AutoDynamic != nil {
+			autoDynamic := *s.AutoDynamic
+			create.AutoDynamic = &autoDynamic
+		}
+		used := make(map[string]bool)
+		for _, a := range s.Actions {
+			lightID, match := inv.findLight(group, a.Light, used)
+			if match != lightFound {
+				plan.Unmatched = append(plan.Unmatched, UnmatchedLight{Scene: s.Name, Light: a.Light, Ambiguous: match == lightAmbiguous})
+				continue
+			}
+			create.Actions = append(create.Actions, scene.ActionTarget{
+				Target: scene.Target{Rid: lightID, Rtype: "light"},
+				Action: a.Action,
+			})
+		}
+		plan.Scenes = append(plan.Scenes, create)
+	}
+	return plan, nil
+}
+
+// ImportResult lists the scenes created on the target bridge.
+type ImportResult struct {
+	Created   []common.Reference
+	Unmatched []UnmatchedLight
+}
+
+// ImportScenes remaps the export to the bridge and creates every scene. Scenes without any matched lights are skipped.
+func ImportScenes(ctx context.Context, c client.HueServiceClient, export *SceneExport) (*ImportResult, error) {
+	inventory, err := LoadInventory(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := inventory.Remap(export)
+	if err != nil {
+		return nil, err
+	}
+	result := &ImportResult{Unmatched: plan.Unmatched}
+	for _, create := range plan.Scenes {
+		if len(create.Actions) == 0 {
+			continue
+		}
+		ref, err := c.SceneService().CreateScene(ctx, create)
+		if err != nil {
+			return result, fmt.Errorf("failed to create scene %q: %w", create.Metadata.Name, err)
+		}
+		result.Created = append(result.Created, *ref)
+	}
+	return result, nil
+}
@@ -0,0 +1,157 @@
+package portable
+
+import (
+	"bytes"
+	"github.com/google/go-cmp/cmp"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/light"
+	"github.com/richseviora/huego/pkg/resources/scene"
+	"testing"
+)
+
+func TestInventory_ExportAndRemap(t *testing.T) {
+	source := NewInventory(
+		[]InventoryGroup{{ID: "room-a", Type: "room", Name: "Kitchen", LightIDs: []string{"light-a1", "light-a2"}}},
+		[]InventoryLight{
+			{ID: "light-a1", Name: "Ceiling", ModelID: "LCA001"},
+			{ID: "light-a2", Name: "Counter", ModelID: "LCL001"},
+		},
+	)
+	target := NewInventory(
+		[]InventoryGroup{{ID: "room-b", Type: "room", Name: "Kitchen", LightIDs: []string{"light-b1", "light-b2"}}},
+		[]InventoryLight{
+			{ID: "light-b1", Name: "Ceiling", ModelID: "LCA001"},
+			{ID: "light-b2", Name: "Pendant", ModelID: "LCL001"},
+		},
+	)
+	on := &scene.On{On: true}
+	ct := &light.ColorTemperature{Mirek: 366}
+	exported, err := source.ExportScene(scene.SceneData{
+		ID:       "scene-a",
+		Metadata: scene.SceneMetadata{Name: "Cooking"},
+		Group:    common.Reference{RID: "room-a", RType: "room"},
+		Actions: []scene.ActionTarget{
+			{Target: scene.Target{Rid: "light-a1", Rtype: "light"}, Action: scene.Action{On: on, ColorTemperature: ct}},
+			{Target: scene.Target{Rid: "light-a2", Rtype: "light"}, Action: scene.Action{On: on}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := (&SceneExport{Version: FormatVersion, Scenes: []Scene{*exported}}).Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	file, err := ReadSceneExport(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := target.Remap(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &ImportPlan{
+		Scenes: []scene.SceneCreate{{
+			Metadata: scene.SceneMetadata{Name: "Cooking"},
+			Group:    common.Reference{RID: "room-b", RType: "room"},
+			Actions: []scene.ActionTarget{
+				{Target: scene.Target{Rid: "light-b1", Rtype: "light"}, Action: scene.Action{On: on, ColorTemperature: ct}},
+			},
+		}},
+		Unmatched: []UnmatchedLight{{Scene: "Cooking", Light: LightRef{Name: "Counter", ModelID: "LCL001"}}},
+	}
+	if diff := cmp.Diff(expected, plan); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSceneExport_YAMLRoundTrip(t *testing.T) {
+	speed := 0.5
+	autoDynamic := true
+	export := &SceneExport{Version: FormatVersion, Scenes: []Scene{{
+		Name:  "Reading",
+		Group: GroupRef{Type: "zone", Name: "Living room"},
+		Actions: []Action{
+			{Light: LightRef{Name: "Floor lamp", ModelID: "LTW001"}, Action: scene.Action{
+				On:               &scene.On{On: true},
+				Dimming:          &common.Dimming{Brightness: 40},
+				ColorTemperature: &light.ColorTemperature{Mirek: 366},
+			}},
+			{Light: LightRef{Name: "yes"}, Action: scene.Action{On: &scene.On{On: false}}},
+		},
+		Speed:       &speed,
+		AutoDynamic: &autoDynamic,
+	}}}
+
+	var buf bytes.Buffer
+	if err := export.WriteYAML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("version: 1\nscenes:\n")) {
+		t.Errorf("WriteYAML() = %s, want block style with fields in order", buf.String())
+	}
+	result, err := ReadSceneExportYAML(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(export, result); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestInventory_RemapMissingGroup(t *testing.T) {
+	target := NewInventory(nil, nil)
+	_, err := target.Remap(&SceneExport{Version: FormatVersion, Scenes: []Scene{{Name: "Cooking", Group: GroupRef{Type: "room", Name: "Kitchen"}}}})
+	if err == nil {
+		t.Errorf("Remap() error = nil, want error")
+	}
+}
+
+func TestInventory_RemapDuplicateNames(t *testing.T) {
+	target := NewInventory(
+		[]InventoryGroup{
+			{ID: "room-1", Type: "room", Name: "Living room", LightIDs: []string{"light-1", "light-2", "light-3"}},
+			{ID: "room-2", Type: "room", Name: "Bedroom"},
+			{ID: "room-3", Type: "room", Name: "Bedroom"},
+		},
+		[]InventoryLight{
+			{ID: "light-1", Name: "Hue color lamp 1", ModelID: "LCA001"},
+			{ID: "light-2", Name: "Hue color lamp 1", ModelID: "LCA001"},
+			{ID: "light-3", Name: "Hue white lamp", ModelID: "LWA001"},
+		},
+	)
+	on := scene.Action{On: &scene.On{On: true}}
+	plan, err := target.Remap(&SceneExport{Version: FormatVersion, Scenes: []Scene{{
+		Name:  "Relax",
+		Group: GroupRef{Type: "room", Name: "Living room"},
+		Actions: []Action{
+			{Light: LightRef{Name: "Hue color lamp 1", ModelID: "LCA001"}, Action: on},
+			{Light: LightRef{Name: "Hue white lamp", ModelID: "LWA001"}, Action: on},
+			{Light: LightRef{Name: "Hue white lamp", ModelID: "LWA001"}, Action: on},
+		},
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &ImportPlan{
+		Scenes: []scene.SceneCreate{{
+			Metadata: scene.SceneMetadata{Name: "Relax"},
+			Group:    common.Reference{RID: "room-1", RType: "room"},
+			Actions:  []scene.ActionTarget{{Target: scene.Target{Rid: "light-3", Rtype: "light"}, Action: on}},
+		}},
+		Unmatched: []UnmatchedLight{
+			{Scene: "Relax", Light: LightRef{Name: "Hue color lamp 1", ModelID: "LCA001"}, Ambiguous: true},
+			{Scene: "Relax", Light: LightRef{Name: "Hue white lamp", ModelID: "LWA001"}},
+		},
+	}
+	if diff := cmp.Diff(expected, plan); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = target.Remap(&SceneExport{Version: FormatVersion, Scenes: []Scene{{Name: "Sleep", Group: GroupRef{Type: "room", Name: "Bedroom"}}}})
+	if err == nil {
+		t.Errorf("Remap() error = nil, want error for duplicate room names")
+	}
+}
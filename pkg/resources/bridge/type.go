@@ -0,0 +1,154 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"sort"
+	"strings"
+	"time"
+)
+
+type TimeZone struct {
+	TimeZone string `json:"time_zone"`
+}
+
+// Data is the bridge resource.
+type Data struct {
+	ID       string           `json:"id"`
+	IDV1     string           `json:"id_v1,omitempty"`
+	Owner    common.Reference `json:"owner"`
+	BridgeID string           `json:"bridge_id"`
+	TimeZone TimeZone         `json:"time_zone"`
+	Type     string           `json:"type"`
+}
+
+var (
+	_ common.Identable = &Data{}
+)
+
+func (d Data) Identity() string {
+	return d.ID
+}
+
+// Config is the public configuration from /api/0/config, which the bridge serves without an application key.
+type Config struct {
+	Name             string `json:"name"`
+	DatastoreVersion string `json:"datastoreversion"`
+	SoftwareVersion  string `json:"swversion"`
+	APIVersion       string `json:"apiversion"`
+	MAC              string `json:"mac"`
+	BridgeID         string `json:"bridgeid"`
+	FactoryNew       bool   `json:"factorynew"`
+	ReplacesBridgeID string `json:"replacesbridgeid"`
+	ModelID          string `json:"modelid"`
+	StarterKitID     string `json:"starterkitid"`
+}
+
+// SameBridge reports whether the config belongs to the bridge with the ID, comparing case-insensitively as the
+// discovery endpoints and the config report IDs in different cases.
+func (c Config) SameBridge(bridgeID string) bool {
+	return strings.EqualFold(c.BridgeID, bridgeID)
+}
+
+// Feature is a bridge capability that needs a minimum API version.
+type Feature struct {
+	Name          string
+	MinAPIVersion string
+}
+
+// FeatureCLIPv2 is the /clip/v2 API every service in this library uses.
+var FeatureCLIPv2 = Feature{Name: "CLIP v2 API", MinAPIVersion: "1.48.0"}
+
+// Incompatibility describes a feature the bridge is too old for.
+type Incompatibility struct {
+	Feature    Feature
+	APIVersion string
+}
+
+func (i Incompatibility) String() string {
+	return fmt.Sprintf("%s requires API version %s, bridge has %s", i.Feature.Name, i.Feature.MinAPIVersion, i.APIVersion)
+}
+
+// Incompatibilities returns the features the bridge's API version is older than.
+func (c Config) Incompatibilities(features ...Feature) []Incompatibility {
+	var result []Incompatibility
+	for _, f := range features {
+		if common.CompareVersions(c.APIVersion, f.MinAPIVersion) < 0 {
+			result = append(result, Incompatibility{Feature: f, APIVersion: c.APIVersion})
+		}
+	}
+	return result
+}
+
+// whitelistTimeLayout is the format of the v1 whitelist dates, which are in UTC without a zone.
+const whitelistTimeLayout = "2006-01-02T15:04:05"
+
+// WhitelistEntry is an application key as reported by the v1 /api/<key>/config whitelist.
+type WhitelistEntry struct {
+	Name        string `json:"name"`
+	CreateDate  string `json:"create date"`
+	LastUseDate string `json:"last use date"`
+}
+
+// AuthenticatedConfig is the part of /api/<key>/config only returned to a valid application key.
+type AuthenticatedConfig struct {
+	Whitelist map[string]WhitelistEntry `json:"whitelist"`
+}
+
+// Application is an application key registered with the bridge. Name is the devicetype it registered with, such as
+// "huego#laptop". Dates the bridge could not report are zero.
+type Application struct {
+	Key        string
+	Name       string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+}
+
+// Applications returns the whitelisted applications, most recently used first.
+func (c AuthenticatedConfig) Applications() []Application {
+	result := make([]Application, 0, len(c.Whitelist))
+	for key, entry := range c.Whitelist {
+		created, _ := time.Parse(whitelistTimeLayout, entry.CreateDate)
+		lastUsed, _ := time.Parse(whitelistTimeLayout, entry.LastUseDate)
+		result = append(result, Application{Key: key, Name: entry.Name, CreatedAt: created, LastUsedAt: lastUsed})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].LastUsedAt.Equal(result[j].LastUsedAt) {
+			return result[i].LastUsedAt.After(result[j].LastUsedAt)
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// APIError is an error reported in the body of a v1 API response.
+type APIError struct {
+	Type        int    `json:"type"`
+	Address     string `json:"address"`
+	Description string `json:"description"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %s (type %d)", e.Address, e.Description, e.Type)
+}
+
+// APIResponse is one element of a v1 API response.
+type APIResponse struct {
+	Success interface{} `json:"success,omitempty"`
+	Error   *APIError   `json:"error,omitempty"`
+}
+
+type Service interface {
+	// GetBridge returns the bridge resource. Every bridge has exactly one.
+	GetBridge(ctx context.Context) (*Data, error)
+	GetConfig(ctx context.Context) (*Config, error)
+	// CheckCompatibility logs a warning for, and returns, every feature the bridge firmware is too old for. The error
+	// is only set when the configuration could not be read.
+	CheckCompatibility(ctx context.Context, features ...Feature) ([]Incompatibility, error)
+	// ListApplications returns the application keys registered with the bridge, most recently used first.
+	ListApplications(ctx context.Context) ([]Application, error)
+	// RevokeApplication removes the application key from the bridge. Errors the bridge reports are returned as
+	// *APIError; recent firmware only allows removing keys through the Hue account and rejects the request.
+	RevokeApplication(ctx context.Context, key string) error
+}
@@ -0,0 +1,71 @@
+package bridge
+
+import (
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+	"time"
+)
+
+const configResponse = `{
+  "name": "Hue Bridge",
+  "datastoreversion": "163",
+  "swversion": "1962154010",
+  "apiversion": "1.62.0",
+  "mac": "ec:b5:fa:00:00:00",
+  "bridgeid": "ECB5FAFFFE000000",
+  "factorynew": false,
+  "replacesbridgeid": null,
+  "modelid": "BSB002",
+  "starterkitid": ""
+}`
+
+func TestConfig(t *testing.T) {
+	var config Config
+	if err := json.Unmarshal([]byte(configResponse), &config); err != nil {
+		t.Fatal(err)
+	}
+	expected := Config{
+		Name:             "Hue Bridge",
+		DatastoreVersion: "163",
+		SoftwareVersion:  "1962154010",
+		APIVersion:       "1.62.0",
+		MAC:              "ec:b5:fa:00:00:00",
+		BridgeID:         "ECB5FAFFFE000000",
+		ModelID:          "BSB002",
+	}
+	if diff := cmp.Diff(expected, config); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+	if !config.SameBridge("ecb5fafffe000000") {
+		t.Errorf("SameBridge() = false, want true")
+	}
+	future := Feature{Name: "future", MinAPIVersion: "1.70.0"}
+	expectedIncompatibilities := []Incompatibility{{Feature: future, APIVersion: "1.62.0"}}
+	if diff := cmp.Diff(expectedIncompatibilities, config.Incompatibilities(FeatureCLIPv2, future)); diff != "" {
+		t.Errorf("Incompatibilities() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestAuthenticatedConfig_Applications(t *testing.T) {
+	var config AuthenticatedConfig
+	err := json.Unmarshal([]byte(`{
+  "name": "Hue Bridge",
+  "whitelist": {
+    "old-key": {"last use date": "2023-05-01T10:00:00", "create date": "2021-01-01T00:00:00", "name": "huego#1234567890"},
+    "new-key": {"last use date": "2024-06-01T12:30:00", "create date": "2024-06-01T12:00:00", "name": "huego#laptop"},
+    "unused": {"create date": "2024-01-01T00:00:00", "name": "Hue#Phone"}
+  }
+}`), &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Application{
+		{Key: "new-key", Name: "huego#laptop", CreatedAt: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC), LastUsedAt: time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC)},
+		{Key: "old-key", Name: "huego#1234567890", CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), LastUsedAt: time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)},
+		{Key: "unused", Name: "Hue#Phone", CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	if diff := cmp.Diff(expected, config.Applications()); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+}
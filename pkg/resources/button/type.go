@@ -0,0 +1,68 @@
+package button
+
+import (
+	"context"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"time"
+)
+
+type Event string
+
+const (
+	InitialPress       Event = "initial_press"
+	Repeat             Event = "repeat"
+	ShortRelease       Event = "short_release"
+	LongRelease        Event = "long_release"
+	DoubleShortRelease Event = "double_short_release"
+	LongPress          Event = "long_press"
+)
+
+type Metadata struct {
+	// ControlID is the position of the button on the device, starting at 1.
+	ControlID int `json:"control_id"`
+}
+
+type Report struct {
+	Updated time.Time `json:"updated"`
+	Event   Event     `json:"event"`
+}
+
+type Button struct {
+	// LastEvent is deprecated by the bridge in favour of ButtonReport.
+	LastEvent    Event   `json:"last_event,omitempty"`
+	ButtonReport *Report `json:"button_report,omitempty"`
+	// RepeatInterval is how often, in milliseconds, Repeat is sent while the button is held.
+	RepeatInterval int     `json:"repeat_interval,omitempty"`
+	EventValues    []Event `json:"event_values,omitempty"`
+}
+
+type Data struct {
+	ID       string           `json:"id"`
+	IDV1     string           `json:"id_v1,omitempty"`
+	Owner    common.Reference `json:"owner"`
+	Metadata Metadata         `json:"metadata"`
+	Button   Button           `json:"button"`
+	Type     string           `json:"type"`
+}
+
+var (
+	_ common.Identable = &Data{}
+)
+
+func (d Data) Identity() string {
+	return d.ID
+}
+
+// LastEvent returns the most recent event and when it happened. The time is zero on firmware that only reports
+// last_event.
+func (d Data) LastEvent() (Event, time.Time) {
+	if d.Button.ButtonReport != nil {
+		return d.Button.ButtonReport.Event, d.Button.ButtonReport.Updated
+	}
+	return d.Button.LastEvent, time.Time{}
+}
+
+type Service interface {
+	GetAllButtons(ctx context.Context) (*common.ResourceList[Data], error)
+	GetButton(ctx context.Context, id string) (*Data, error)
+}
@@ -3,12 +3,24 @@ package client
 import (
 	"github.com/richseviora/huego/pkg/resources/behavior_instance"
 	"github.com/richseviora/huego/pkg/resources/behavior_script"
+	"github.com/richseviora/huego/pkg/resources/bridge"
+	"github.com/richseviora/huego/pkg/resources/button"
+	"github.com/richseviora/huego/pkg/resources/contact"
 	"github.com/richseviora/huego/pkg/resources/device"
+	"github.com/richseviora/huego/pkg/resources/device_power"
+	"github.com/richseviora/huego/pkg/resources/device_software_update"
+	"github.com/richseviora/huego/pkg/resources/event"
 	"github.com/richseviora/huego/pkg/resources/light"
+	"github.com/richseviora/huego/pkg/resources/light_level"
 	"github.com/richseviora/huego/pkg/resources/motion"
+	"github.com/richseviora/huego/pkg/resources/relative_rotary"
 	"github.com/richseviora/huego/pkg/resources/room"
 	"github.com/richseviora/huego/pkg/resources/scene"
+	"github.com/richseviora/huego/pkg/resources/smart_scene"
+	"github.com/richseviora/huego/pkg/resources/tamper"
+	"github.com/richseviora/huego/pkg/resources/temperature"
 	"github.com/richseviora/huego/pkg/resources/zigbee_connectivity"
+	"github.com/richseviora/huego/pkg/resources/zigbee_device_discovery"
 	"github.com/richseviora/huego/pkg/resources/zone"
 )
 
@@ -22,6 +34,18 @@ type HueServiceClient interface {
 	BehaviorInstanceService() behavior_instance.Service
 	BehaviorScriptService() behavior_script.Service
 	MotionService() motion.Service
+	SmartSceneService() smart_scene.Service
+	BridgeService() bridge.Service
+	ZigbeeDeviceDiscoveryService() zigbee_device_discovery.Service
+	TamperService() tamper.Service
+	ContactService() contact.Service
+	EventService() event.Service
+	RelativeRotaryService() relative_rotary.Service
+	ButtonService() button.Service
+	LightLevelService() light_level.Service
+	TemperatureService() temperature.Service
+	DeviceSoftwareUpdateService() device_software_update.Service
+	DevicePowerService() device_power.Service
 }
 
 type PersistentClientProvider interface {
@@ -1,56 +1,109 @@
 package common
 
 import (
-	"encoding/json"
-	"fmt"
+	"errors"
+	"strings"
 )
 
-type Area int
+// Area is the archetype of a room or zone. It holds the bridge's snake-case token, so archetypes added by newer
+// bridge firmware are preserved and sent back unchanged on update.
+type Area string
 
 const (
-	LivingRoom Area = iota
-	Kitchen
-	Dining
-	Bedroom
-	KidsBedroom
-	Bathroom
-	Nursery
-	Recreation
-	Office
-	Gym
-	Hallway
-	Toilet
-	FrontDoor
-	Garage
-	Terrace
-	Garden
-	Driveway
-	Carport
-	Home
-	Downstairs
-	Upstairs
-	TopFloor
-	Attic
-	GuestRoom
-	Staircase
-	Lounge
-	ManCave
-	Computer
-	Studio
-	Music
-	TV
-	Reading
-	Closet
-	Storage
-	LaundryRoom
-	Balcony
-	Porch
-	Barbecue
-	Pool
-	Other
-	InvalidArea
+	LivingRoom  Area = "living_room"
+	Kitchen     Area = "kitchen"
+	Dining      Area = "dining"
+	Bedroom     Area = "bedroom"
+	KidsBedroom Area = "kids_bedroom"
+	Bathroom    Area = "bathroom"
+	Nursery     Area = "nursery"
+	Recreation  Area = "recreation"
+	Office      Area = "office"
+	Gym         Area = "gym"
+	Hallway     Area = "hallway"
+	Toilet      Area = "toilet"
+	FrontDoor   Area = "front_door"
+	Garage      Area = "garage"
+	Terrace     Area = "terrace"
+	Garden      Area = "garden"
+	Driveway    Area = "driveway"
+	Carport     Area = "carport"
+	Home        Area = "home"
+	Downstairs  Area = "downstairs"
+	Upstairs    Area = "upstairs"
+	TopFloor    Area = "top_floor"
+	Attic       Area = "attic"
+	GuestRoom   Area = "guest_room"
+	Staircase   Area = "staircase"
+	Lounge      Area = "lounge"
+	ManCave     Area = "man_cave"
+	Computer    Area = "computer"
+	Studio      Area = "studio"
+	Music       Area = "music"
+	TV          Area = "tv"
+	Reading     Area = "reading"
+	Closet      Area = "closet"
+	Storage     Area = "storage"
+	LaundryRoom Area = "laundry_room"
+	Balcony     Area = "balcony"
+	Porch       Area = "porch"
+	Barbecue    Area = "barbecue"
+	Pool        Area = "pool"
+	Other       Area = "other"
+	// InvalidArea is returned by ParseArea when the input is empty.
+	InvalidArea Area = "invalid_area"
 )
 
+// AreaInfo holds presentation metadata for an archetype. Icon is a generic icon name a UI can map to its own assets.
+type AreaInfo struct {
+	DisplayName string
+	Icon        string
+}
+
+var areaInfo = map[Area]AreaInfo{
+	LivingRoom:  {"Living room", "sofa"},
+	Kitchen:     {"Kitchen", "kitchen"},
+	Dining:      {"Dining", "dining"},
+	Bedroom:     {"Bedroom", "bed"},
+	KidsBedroom: {"Kids bedroom", "child"},
+	Bathroom:    {"Bathroom", "bathtub"},
+	Nursery:     {"Nursery", "crib"},
+	Recreation:  {"Recreation", "games"},
+	Office:      {"Office", "desk"},
+	Gym:         {"Gym", "dumbbell"},
+	Hallway:     {"Hallway", "hallway"},
+	Toilet:      {"Toilet", "toilet"},
+	FrontDoor:   {"Front door", "door"},
+	Garage:      {"Garage", "garage"},
+	Terrace:     {"Terrace", "deck"},
+	Garden:      {"Garden", "tree"},
+	Driveway:    {"Driveway", "road"},
+	Carport:     {"Carport", "car"},
+	Home:        {"Home", "home"},
+	Downstairs:  {"Downstairs", "stairs_down"},
+	Upstairs:    {"Upstairs", "stairs_up"},
+	TopFloor:    {"Top floor", "roof"},
+	Attic:       {"Attic", "attic"},
+	GuestRoom:   {"Guest room", "bed"},
+	Staircase:   {"Staircase", "stairs"},
+	Lounge:      {"Lounge", "armchair"},
+	ManCave:     {"Man cave", "beer"},
+	Computer:    {"Computer", "computer"},
+	Studio:      {"Studio", "palette"},
+	Music:       {"Music", "music"},
+	TV:          {"TV", "tv"},
+	Reading:     {"Reading", "book"},
+	Closet:      {"Closet", "hanger"},
+	Storage:     {"Storage", "box"},
+	LaundryRoom: {"Laundry room", "washing_machine"},
+	Balcony:     {"Balcony", "balcony"},
+	Porch:       {"Porch", "porch"},
+	Barbecue:    {"Barbecue", "grill"},
+	Pool:        {"Pool", "pool"},
+	Other:       {"Other", "other"},
+}
+
+// AreaNames lists the tokens of every archetype known to this library.
 var AreaNames = [...]string{
 	"living_room",
 	"kitchen",
@@ -92,48 +145,42 @@ var AreaNames = [...]string{
 	"barbecue",
 	"pool",
 	"other",
-	"invalid_area",
 }
 
-// String returns the original snake-case token (or "Area(<n>)" if out of range).
-func (a *Area) String() string {
-	var s string
-	if a == nil {
-		return AreaNames[InvalidArea]
-	}
-	index := int(*a)
-	if index < 0 || index >= len(AreaNames) {
-		return fmt.Sprintf("Area(%d)", a)
-	}
-	s = AreaNames[index]
-	return s
+// String returns the original snake-case token.
+func (a Area) String() string {
+	return string(a)
+}
+
+// IsKnown reports whether the archetype is one this library has metadata for.
+func (a Area) IsKnown() bool {
+	_, ok := areaInfo[a]
+	return ok
 }
 
-// UnmarshalJSON implements json.Unmarshaler interface for Area
-func (a *Area) UnmarshalJSON(data []byte) error {
-	var s string
-	if err := json.Unmarshal(data, &s); err != nil {
-		return err
+// Info returns the display name and icon for the archetype. Unknown archetypes get a display name derived from the
+// token and the "other" icon.
+func (a Area) Info() AreaInfo {
+	if info, ok := areaInfo[a]; ok {
+		return info
 	}
-	parsed, err := ParseArea(s)
-	if err != nil {
-		return err
+	name := strings.ReplaceAll(string(a), "_", " ")
+	if name != "" {
+		name = strings.ToUpper(name[:1]) + name[1:]
 	}
-	*a = parsed
-	return nil
+	return AreaInfo{DisplayName: name, Icon: areaInfo[Other].Icon}
 }
 
-// Value receiver left because
-func (a Area) MarshalJSON() ([]byte, error) {
-	return json.Marshal(a.String())
+// DisplayName returns a human-readable name for the archetype.
+func (a Area) DisplayName() string {
+	return a.Info().DisplayName
 }
 
-// ParseArea converts a string like "living_room" back into the enum.
+// ParseArea converts a string like "living_room" into an Area. Unknown tokens are accepted so that new archetypes do
+// not break decoding; only an empty string is an error.
 func ParseArea(s string) (Area, error) {
-	for i, name := range AreaNames {
-		if name == s {
-			return Area(i), nil
-		}
+	if s == "" {
+		return InvalidArea, errors.New("empty area")
 	}
-	return InvalidArea, fmt.Errorf("unknown area: %q", s)
+	return Area(s), nil
 }
@@ -85,3 +85,36 @@ func TestArea_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestArea_UnknownRoundTrip(t *testing.T) {
+	input := `{"name":"sauna_room"}`
+	var result TestArea
+	if err := json.Unmarshal([]byte(input), &result); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if result.Name.IsKnown() {
+		t.Errorf("IsKnown() = true, want false")
+	}
+	if result.Name.DisplayName() != "Sauna room" {
+		t.Errorf("DisplayName() = %v, want %v", result.Name.DisplayName(), "Sauna room")
+	}
+	output, err := json.Marshal(&result)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(output) != input {
+		t.Errorf("json.Marshal() = %v, want %v", string(output), input)
+	}
+}
+
+func TestArea_Info(t *testing.T) {
+	for _, name := range AreaNames {
+		area := Area(name)
+		if !area.IsKnown() {
+			t.Errorf("%s IsKnown() = false, want true", name)
+		}
+		if area.Info().DisplayName == "" || area.Info().Icon == "" {
+			t.Errorf("%s Info() = %+v, want display name and icon", name, area.Info())
+		}
+	}
+}
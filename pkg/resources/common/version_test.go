@@ -0,0 +1,23 @@
+package common
+
+import (
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.116.3", "1.116.3", 0},
+		{"1.93.11", "1.116.3", -1},
+		{"1.116.3", "1.116", 1},
+		{"", "1.0", -1},
+		{"1.2.b", "1.2.a", 1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.expected {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
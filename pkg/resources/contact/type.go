@@ -0,0 +1,56 @@
+package contact
+
+import (
+	"context"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"time"
+)
+
+type State string
+
+const (
+	// Contact means the two parts of the sensor are together, i.e. the door is closed.
+	Contact   State = "contact"
+	NoContact State = "no_contact"
+)
+
+type Report struct {
+	Changed time.Time `json:"changed"`
+	State   State     `json:"state"`
+}
+
+type Data struct {
+	ID      string           `json:"id"`
+	IDV1    string           `json:"id_v1,omitempty"`
+	Owner   common.Reference `json:"owner"`
+	Enabled bool             `json:"enabled"`
+	// ContactReport is nil until the sensor has reported a state.
+	ContactReport *Report `json:"contact_report,omitempty"`
+	Type          string  `json:"type"`
+}
+
+var (
+	_ common.Identable = &Data{}
+)
+
+func (d Data) Identity() string {
+	return d.ID
+}
+
+// IsOpen reports whether the sensor parts are apart. ok is false when the sensor has not reported a state.
+func (d Data) IsOpen() (open bool, ok bool) {
+	if d.ContactReport == nil {
+		return false, false
+	}
+	return d.ContactReport.State == NoContact, true
+}
+
+type UpdateRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+type Service interface {
+	GetAllContacts(ctx context.Context) (*common.ResourceList[Data], error)
+	GetContact(ctx context.Context, id string) (*Data, error)
+	UpdateContact(ctx context.Context, id string, update UpdateRequest) (*common.Reference, error)
+}
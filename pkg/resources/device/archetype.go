@@ -0,0 +1,110 @@
+package device
+
+import (
+	"strings"
+)
+
+// ProductArchetype is the kind of product a device is, used by the Hue app to pick its icon. Like common.Area it
+// holds the bridge's snake-case token so archetypes added by newer firmware survive a round trip.
+type ProductArchetype string
+
+const (
+	BridgeV2            ProductArchetype = "bridge_v2"
+	UnknownArchetype    ProductArchetype = "unknown_archetype"
+	ClassicBulb         ProductArchetype = "classic_bulb"
+	SultanBulb          ProductArchetype = "sultan_bulb"
+	FloodBulb           ProductArchetype = "flood_bulb"
+	SpotBulb            ProductArchetype = "spot_bulb"
+	CandleBulb          ProductArchetype = "candle_bulb"
+	LusterBulb          ProductArchetype = "luster_bulb"
+	PendantRound        ProductArchetype = "pendant_round"
+	PendantLong         ProductArchetype = "pendant_long"
+	CeilingRound        ProductArchetype = "ceiling_round"
+	CeilingSquare       ProductArchetype = "ceiling_square"
+	FloorShade          ProductArchetype = "floor_shade"
+	FloorLantern        ProductArchetype = "floor_lantern"
+	TableShade          ProductArchetype = "table_shade"
+	RecessedCeiling     ProductArchetype = "recessed_ceiling"
+	RecessedFloor       ProductArchetype = "recessed_floor"
+	SingleSpot          ProductArchetype = "single_spot"
+	DoubleSpot          ProductArchetype = "double_spot"
+	TableWash           ProductArchetype = "table_wash"
+	WallLantern         ProductArchetype = "wall_lantern"
+	WallShade           ProductArchetype = "wall_shade"
+	FlexibleLamp        ProductArchetype = "flexible_lamp"
+	GroundSpot          ProductArchetype = "ground_spot"
+	WallSpot            ProductArchetype = "wall_spot"
+	Plug                ProductArchetype = "plug"
+	HueGo               ProductArchetype = "hue_go"
+	HueLightstrip       ProductArchetype = "hue_lightstrip"
+	HueIris             ProductArchetype = "hue_iris"
+	HueBloom            ProductArchetype = "hue_bloom"
+	Bollard             ProductArchetype = "bollard"
+	WallWasher          ProductArchetype = "wall_washer"
+	HuePlay             ProductArchetype = "hue_play"
+	VintageBulb         ProductArchetype = "vintage_bulb"
+	VintageCandleBulb   ProductArchetype = "vintage_candle_bulb"
+	EllipseBulb         ProductArchetype = "ellipse_bulb"
+	TriangleBulb        ProductArchetype = "triangle_bulb"
+	SmallGlobeBulb      ProductArchetype = "small_globe_bulb"
+	LargeGlobeBulb      ProductArchetype = "large_globe_bulb"
+	EdisonBulb          ProductArchetype = "edison_bulb"
+	ChristmasTree       ProductArchetype = "christmas_tree"
+	StringLight         ProductArchetype = "string_light"
+	HueCentris          ProductArchetype = "hue_centris"
+	HueLightstripTV     ProductArchetype = "hue_lightstrip_tv"
+	HueLightstripPC     ProductArchetype = "hue_lightstrip_pc"
+	HueTube             ProductArchetype = "hue_tube"
+	HueSigne            ProductArchetype = "hue_signe"
+	PendantSpot         ProductArchetype = "pendant_spot"
+	CeilingHorizontal   ProductArchetype = "ceiling_horizontal"
+	CeilingTube         ProductArchetype = "ceiling_tube"
+	UpAndDown           ProductArchetype = "up_and_down"
+	UpAndDownUp         ProductArchetype = "up_and_down_up"
+	UpAndDownDown       ProductArchetype = "up_and_down_down"
+	HueFloodlightCamera ProductArchetype = "hue_floodlight_camera"
+	Twilight            ProductArchetype = "twilight"
+	TwilightFront       ProductArchetype = "twilight_front"
+	TwilightBack        ProductArchetype = "twilight_back"
+	HuePlayWallwasher   ProductArchetype = "hue_play_wallwasher"
+	HueOmniglow         ProductArchetype = "hue_omniglow"
+	HueNeon             ProductArchetype = "hue_neon"
+	StringGlobe         ProductArchetype = "string_globe"
+	StringPermanent     ProductArchetype = "string_permanent"
+)
+
+// ProductArchetypes lists every archetype this library knows about, in the order the API documents them.
+var ProductArchetypes = []ProductArchetype{
+	BridgeV2, UnknownArchetype, ClassicBulb, SultanBulb, FloodBulb, SpotBulb, CandleBulb, LusterBulb, PendantRound,
+	PendantLong, CeilingRound, CeilingSquare, FloorShade, FloorLantern, TableShade, RecessedCeiling, RecessedFloor,
+	SingleSpot, DoubleSpot, TableWash, WallLantern, WallShade, FlexibleLamp, GroundSpot, WallSpot, Plug, HueGo,
+	HueLightstrip, HueIris, HueBloom, Bollard, WallWasher, HuePlay, VintageBulb, VintageCandleBulb, EllipseBulb,
+	TriangleBulb, SmallGlobeBulb, LargeGlobeBulb, EdisonBulb, ChristmasTree, StringLight, HueCentris,
+	HueLightstripTV, HueLightstripPC, HueTube, HueSigne, PendantSpot, CeilingHorizontal, CeilingTube, UpAndDown,
+	UpAndDownUp, UpAndDownDown, HueFloodlightCamera, Twilight, TwilightFront, TwilightBack, HuePlayWallwasher,
+	HueOmniglow, HueNeon, StringGlobe, StringPermanent,
+}
+
+// String returns the original snake-case token.
+func (a ProductArchetype) String() string {
+	return string(a)
+}
+
+// IsKnown reports whether the archetype is listed in ProductArchetypes.
+func (a ProductArchetype) IsKnown() bool {
+	for _, known := range ProductArchetypes {
+		if a == known {
+			return true
+		}
+	}
+	return false
+}
+
+// DisplayName returns the token with underscores replaced by spaces and the first letter capitalised.
+func (a ProductArchetype) DisplayName() string {
+	name := strings.ReplaceAll(string(a), "_", " ")
+	if name != "" {
+		name = strings.ToUpper(name[:1]) + name[1:]
+	}
+	return name
+}
@@ -2,7 +2,7 @@ package device
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"github.com/richseviora/huego/pkg/resources/common"
 )
 
@@ -11,20 +11,43 @@ type AutoGenerated struct {
 	Data   []Data `json:"data"`
 }
 type ProductData struct {
-	ModelID              string `json:"model_id"`
-	ManufacturerName     string `json:"manufacturer_name"`
-	ProductName          string `json:"product_name"`
-	ProductArchetype     string `json:"product_archetype"`
-	Certified            bool   `json:"certified"`
-	SoftwareVersion      string `json:"software_version"`
-	HardwarePlatformType string `json:"hardware_platform_type,omitempty"`
+	ModelID              string           `json:"model_id"`
+	ManufacturerName     string           `json:"manufacturer_name"`
+	ProductName          string           `json:"product_name"`
+	ProductArchetype     ProductArchetype `json:"product_archetype"`
+	Certified            bool             `json:"certified"`
+	SoftwareVersion      string           `json:"software_version"`
+	HardwarePlatformType string           `json:"hardware_platform_type,omitempty"`
 }
 type Metadata struct {
-	Name      string `json:"name"`
-	Archetype string `json:"archetype"`
+	Name      string           `json:"name"`
+	Archetype ProductArchetype `json:"archetype"`
 }
 type Identify struct {
 }
+
+// IdentifyAction makes the device blink or beep once so it can be found.
+const IdentifyAction = "identify"
+
+type IdentifyUpdate struct {
+	Action string `json:"action"`
+}
+
+type MetadataUpdate struct {
+	Name      string           `json:"name,omitempty"`
+	Archetype ProductArchetype `json:"archetype,omitempty"`
+}
+
+type UsertestUpdate struct {
+	Usertest bool `json:"usertest"`
+}
+
+// Update changes a device. Only the non-nil fields are sent.
+type Update struct {
+	Metadata *MetadataUpdate `json:"metadata,omitempty"`
+	Identify *IdentifyUpdate `json:"identify,omitempty"`
+	Usertest *UsertestUpdate `json:"usertest,omitempty"`
+}
 type Services struct {
 	Rid   string `json:"rid"`
 	Rtype string `json:"rtype"`
@@ -53,25 +76,60 @@ func (d Data) Identity() string {
 	return d.ID
 }
 
-func (d Data) GetLightServiceID() (string, error) {
+// GetServiceID returns the ID of the first service of the given type, such as "light" or "contact".
+func (d Data) GetServiceID(rtype string) (string, error) {
 	for _, service := range d.Services {
-		if service.Rtype == "light" {
+		if service.Rtype == rtype {
 			return service.Rid, nil
 		}
 	}
-	return "", errors.New("no light service found")
+	return "", fmt.Errorf("no %s service found", rtype)
 }
 
-func (d Data) GetZigbeeConnectivityServiceID() (string, error) {
+// GetServiceIDs returns the IDs of every service of the given type. Devices such as dimmer switches have several
+// button services.
+func (d Data) GetServiceIDs(rtype string) []string {
+	var result []string
 	for _, service := range d.Services {
-		if service.Rtype == "zigbee_connectivity" {
-			return service.Rid, nil
+		if service.Rtype == rtype {
+			result = append(result, service.Rid)
 		}
 	}
-	return "", errors.New("no zigbee_connectivity service found")
+	return result
+}
+
+func (d Data) GetLightServiceID() (string, error) {
+	return d.GetServiceID("light")
+}
+
+func (d Data) GetZigbeeConnectivityServiceID() (string, error) {
+	return d.GetServiceID("zigbee_connectivity")
+}
+
+func (d Data) GetContactServiceID() (string, error) {
+	return d.GetServiceID("contact")
+}
+
+func (d Data) GetTamperServiceID() (string, error) {
+	return d.GetServiceID("tamper")
+}
+
+func (d Data) GetMotionServiceID() (string, error) {
+	return d.GetServiceID("motion")
+}
+
+func (d Data) GetDevicePowerServiceID() (string, error) {
+	return d.GetServiceID("device_power")
 }
 
 type Service interface {
 	GetAllDevices(ctx context.Context) (*common.ResourceList[Data], error)
 	GetDevice(ctx context.Context, id string) (*Data, error)
+	UpdateDevice(ctx context.Context, id string, update Update) (*common.Reference, error)
+	// RenameDevice sets the device name, leaving its archetype unchanged.
+	RenameDevice(ctx context.Context, id string, name string) (*common.Reference, error)
+	// IdentifyDevice makes the device blink or beep once.
+	IdentifyDevice(ctx context.Context, id string) (*common.Reference, error)
+	// DeleteDevice removes the device from the bridge. It has to be paired again to be used.
+	DeleteDevice(ctx context.Context, id string) error
 }
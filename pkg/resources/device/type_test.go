@@ -0,0 +1,59 @@
+package device
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUpdate_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		update   Update
+		expected string
+	}{
+		{"rename", Update{Metadata: &MetadataUpdate{Name: "Hall"}}, `{"metadata":{"name":"Hall"}}`},
+		{"identify", Update{Identify: &IdentifyUpdate{Action: IdentifyAction}}, `{"identify":{"action":"identify"}}`},
+		{"usertest off", Update{Usertest: &UsertestUpdate{}}, `{"usertest":{"usertest":false}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.update)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.expected {
+				t.Errorf("Marshal() = %s, want %s", b, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProductArchetype(t *testing.T) {
+	if !HueLightstripTV.IsKnown() || ProductArchetype("hue_new_thing").IsKnown() {
+		t.Errorf("IsKnown() mismatch")
+	}
+	if got := CeilingRound.DisplayName(); got != "Ceiling round" {
+		t.Errorf("DisplayName() = %s, want Ceiling round", got)
+	}
+}
+
+func TestData_GetServiceID(t *testing.T) {
+	d := Data{Services: []Services{
+		{Rid: "c1", Rtype: "contact"},
+		{Rid: "t1", Rtype: "tamper"},
+		{Rid: "b1", Rtype: "button"},
+		{Rid: "b2", Rtype: "button"},
+	}}
+	if id, err := d.GetContactServiceID(); err != nil || id != "c1" {
+		t.Errorf("GetContactServiceID() = %s, %v, want c1", id, err)
+	}
+	if id, err := d.GetTamperServiceID(); err != nil || id != "t1" {
+		t.Errorf("GetTamperServiceID() = %s, %v, want t1", id, err)
+	}
+	if _, err := d.GetLightServiceID(); err == nil || err.Error() != "no light service found" {
+		t.Errorf("GetLightServiceID() error = %v, want no light service found", err)
+	}
+	if ids := d.GetServiceIDs("button"); len(ids) != 2 {
+		t.Errorf("GetServiceIDs(button) = %v, want 2 IDs", ids)
+	}
+}
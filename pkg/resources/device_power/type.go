@@ -0,0 +1,45 @@
+package device_power
+
+import (
+	"context"
+	"github.com/richseviora/huego/pkg/resources/common"
+)
+
+type BatteryState string
+
+const (
+	BatteryNormal   BatteryState = "normal"
+	BatteryLow      BatteryState = "low"
+	BatteryCritical BatteryState = "critical"
+)
+
+// PowerState is only populated for battery powered devices; mains powered devices report an empty state.
+type PowerState struct {
+	BatteryState BatteryState `json:"battery_state,omitempty"`
+	// BatteryLevel is the remaining charge in percent, nil when the device does not report it.
+	BatteryLevel *int `json:"battery_level,omitempty"`
+}
+
+type Data struct {
+	ID         string           `json:"id"`
+	IDV1       string           `json:"id_v1,omitempty"`
+	Owner      common.Reference `json:"owner"`
+	PowerState PowerState       `json:"power_state"`
+	Type       string           `json:"type"`
+}
+
+var _ common.Identable = &Data{}
+
+func (d Data) Identity() string {
+	return d.ID
+}
+
+// HasBattery reports whether the device reported a battery state or level.
+func (d Data) HasBattery() bool {
+	return d.PowerState.BatteryState != "" || d.PowerState.BatteryLevel != nil
+}
+
+type Service interface {
+	GetAllDevicePower(ctx context.Context) (*common.ResourceList[Data], error)
+	GetDevicePower(ctx context.Context, id string) (*Data, error)
+}
@@ -0,0 +1,43 @@
+package device_software_update
+
+import (
+	"context"
+	"github.com/richseviora/huego/pkg/resources/common"
+)
+
+type State string
+
+const (
+	NoUpdate       State = "no_update"
+	UpdatePending  State = "update_pending"
+	ReadyToInstall State = "ready_to_install"
+	Installing     State = "installing"
+)
+
+type Data struct {
+	ID    string           `json:"id"`
+	Owner common.Reference `json:"owner"`
+	State State            `json:"state"`
+	// Problems lists reasons the update cannot progress, such as "no_update_for_type" or "device_unreachable".
+	Problems []string `json:"problems,omitempty"`
+	Type     string   `json:"type"`
+}
+
+var _ common.Identable = &Data{}
+
+func (d Data) Identity() string {
+	return d.ID
+}
+
+type UpdateRequest struct {
+	// Install asks the bridge to install an update that is ready_to_install.
+	Install bool `json:"install"`
+}
+
+type Service interface {
+	GetAllDeviceSoftwareUpdates(ctx context.Context) (*common.ResourceList[Data], error)
+	GetDeviceSoftwareUpdate(ctx context.Context, id string) (*Data, error)
+	// InstallDeviceSoftwareUpdate triggers installation of the pending update. The bridge rejects the request unless
+	// the state is ReadyToInstall.
+	InstallDeviceSoftwareUpdate(ctx context.Context, id string) (*common.Reference, error)
+}
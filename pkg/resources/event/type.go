@@ -0,0 +1,73 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"time"
+)
+
+type Type string
+
+const (
+	Update Type = "update"
+	Add    Type = "add"
+	Delete Type = "delete"
+	Error  Type = "error"
+)
+
+// Event is one message from the bridge's event stream. Data holds the changed resources; update events only carry the
+// fields that changed, plus id, type and owner.
+type Event struct {
+	CreationTime time.Time         `json:"creationtime"`
+	ID           string            `json:"id"`
+	Type         Type              `json:"type"`
+	Data         []json.RawMessage `json:"data"`
+}
+
+// Header is the part every resource in Event.Data has in common.
+type Header struct {
+	ID    string            `json:"id"`
+	Type  string            `json:"type"`
+	Owner *common.Reference `json:"owner,omitempty"`
+}
+
+// Resources returns the header of every resource in the event.
+func (e Event) Resources() ([]Header, error) {
+	result := make([]Header, 0, len(e.Data))
+	for _, raw := range e.Data {
+		var h Header
+		if err := json.Unmarshal(raw, &h); err != nil {
+			return nil, err
+		}
+		result = append(result, h)
+	}
+	return result, nil
+}
+
+// Decode unmarshals the resources of the given type in the event into T, skipping the others.
+func Decode[T any](e Event, resourceType string) ([]T, error) {
+	var result []T
+	for _, raw := range e.Data {
+		var h Header
+		if err := json.Unmarshal(raw, &h); err != nil {
+			return nil, err
+		}
+		if h.Type != resourceType {
+			continue
+		}
+		var item T
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+type Service interface {
+	// Subscribe connects to the event stream. The first connection is made before returning so that errors such as an
+	// invalid application key are reported. Afterwards the stream is reconnected whenever it drops, and the channel is
+	// closed once ctx is done.
+	Subscribe(ctx context.Context) (<-chan Event, error)
+}
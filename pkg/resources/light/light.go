@@ -17,8 +17,9 @@ type LightOn struct {
 }
 
 type DimmingInfo struct {
-	Brightness  float64 `json:"brightness"`
-	MinDimLevel float64 `json:"min_dim_level"`
+	Brightness float64 `json:"brightness"`
+	// MinDimLevel is only reported by some lights.
+	MinDimLevel *float64 `json:"min_dim_level,omitempty"`
 }
 
 type ColorGamut struct {
@@ -31,6 +32,20 @@ type Color struct {
 	XY color.XYCoord `json:"xy"`
 }
 
+// Contains reports whether the xy coordinate lies within the gamut triangle.
+func (g ColorGamut) Contains(xy color.XYCoord) bool {
+	d1 := crossProduct(xy, g.Red, g.Green)
+	d2 := crossProduct(xy, g.Green, g.Blue)
+	d3 := crossProduct(xy, g.Blue, g.Red)
+	hasNegative := d1 < 0 || d2 < 0 || d3 < 0
+	hasPositive := d1 > 0 || d2 > 0 || d3 > 0
+	return !(hasNegative && hasPositive)
+}
+
+func crossProduct(p, a, b color.XYCoord) float64 {
+	return (p.X-b.X)*(a.Y-b.Y) - (a.X-b.X)*(p.Y-b.Y)
+}
+
 type ColorInfo struct {
 	XY        color.XYCoord `json:"xy"`
 	Gamut     ColorGamut    `json:"gamut"`
@@ -47,15 +62,70 @@ type ColorTemperatureInfo struct {
 	MirekSchema struct {
 		MirekMinimum int `json:"mirek_minimum"`
 		MirekMaximum int `json:"mirek_maximum"`
-	}
+	} `json:"mirek_schema"`
+}
+
+// GradientPoint is a single colour stop in a gradient.
+type GradientPoint struct {
+	Color Color `json:"color"`
+}
+
+// Gradient is the gradient state that can be applied to a gradient-capable light.
+type Gradient struct {
+	Points []GradientPoint `json:"points"`
+	Mode   string          `json:"mode,omitempty"`
+}
+
+type GradientInfo struct {
+	Points        []GradientPoint `json:"points"`
+	Mode          string          `json:"mode"`
+	PointsCapable int             `json:"points_capable"`
+	ModeValues    []string        `json:"mode_values"`
+	PixelCount    int             `json:"pixel_count"`
+}
+
+type EffectsInfo struct {
+	Status       string   `json:"status"`
+	StatusValues []string `json:"status_values"`
+	EffectValues []string `json:"effect_values"`
+}
+
+type EffectsV2Info struct {
+	Action struct {
+		EffectValues []string `json:"effect_values"`
+	} `json:"action"`
+	Status struct {
+		Effect       string   `json:"effect"`
+		EffectValues []string `json:"effect_values"`
+	} `json:"status"`
+}
+
+// NoEffect is the effect value reported by lights that are not running an effect.
+const NoEffect = "no_effect"
+
+type LightMetadataUpdate struct {
+	Name     *string `json:"name,omitempty"`
+	Function *string `json:"function,omitempty"`
+}
+
+// PowerUp presets decide the state a light returns to when mains power is restored.
+const (
+	PowerUpSafety      = "safety"
+	PowerUpPowerfail   = "powerfail"
+	PowerUpLastOnState = "last_on_state"
+	PowerUpCustom      = "custom"
+)
+
+// PowerUpUpdate selects a preset. The custom preset also needs the on, dimming and color settings, which are kept
+// from the light's current configuration when omitted.
+type PowerUpUpdate struct {
+	Preset string `json:"preset"`
 }
 
 type LightUpdate struct {
-	ID       string `json:-`
-	Metadata *struct {
-		Name     *string `json:"name"`
-		Function *string `json:"function"`
-	} `json:"metadata"`
+	ID       string               `json:"-"`
+	Metadata *LightMetadataUpdate `json:"metadata,omitempty"`
+	PowerUp  *PowerUpUpdate       `json:"powerup,omitempty"`
 }
 
 type PowerUp struct {
@@ -77,21 +147,46 @@ type PowerUp struct {
 
 // Light represents the light resource data
 type Light struct {
-	ID        string               `json:"id"`
-	IDv1      string               `json:"idv1"`
-	Metadata  LightMetadata        `json:"metadata"`
-	Owner     common.Reference     `json:"owner"`
-	On        LightOn              `json:"on"`
-	Dimming   DimmingInfo          `json:"dimming"`
-	ColorTemp ColorTemperatureInfo `json:"color_temperature"`
-	Color     Color                `json:"color"`
-	Type      string               `json:"type"`
+	ID       string           `json:"id"`
+	IDv1     string           `json:"idv1"`
+	Metadata LightMetadata    `json:"metadata"`
+	Owner    common.Reference `json:"owner"`
+	On       LightOn          `json:"on"`
+	// Dimming, ColorTemp and Color are nil when the light does not have the capability.
+	Dimming   *DimmingInfo          `json:"dimming,omitempty"`
+	ColorTemp *ColorTemperatureInfo `json:"color_temperature,omitempty"`
+	Color     *ColorInfo            `json:"color,omitempty"`
+	Gradient  *GradientInfo         `json:"gradient,omitempty"`
+	Effects   *EffectsInfo          `json:"effects,omitempty"`
+	EffectsV2 *EffectsV2Info        `json:"effects_v2,omitempty"`
+	PowerUp   *PowerUp              `json:"powerup,omitempty"`
+	Type      string                `json:"type"`
 }
 
 func (l Light) Identity() string {
 	return l.ID
 }
 
+// SupportsDimming reports whether the light reported a dimming state. On/off plugs do not.
+func (l Light) SupportsDimming() bool {
+	return l.Dimming != nil
+}
+
+// SupportsColor reports whether the light reported a colour state.
+func (l Light) SupportsColor() bool {
+	return l.Color != nil
+}
+
+// SupportsColorTemperature reports whether the light reported a colour temperature state.
+func (l Light) SupportsColorTemperature() bool {
+	return l.ColorTemp != nil
+}
+
+// SupportsGradient reports whether the light is gradient capable.
+func (l Light) SupportsGradient() bool {
+	return l.Gradient != nil && l.Gradient.PointsCapable > 0
+}
+
 var (
 	_ common.Identable = &Light{}
 )
@@ -0,0 +1,90 @@
+package light_level
+
+import (
+	"context"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"math"
+	"time"
+)
+
+type LightLevelReport struct {
+	Changed time.Time `json:"changed"`
+	// LightLevel is 10000*log10(lux)+1, see ToLux.
+	LightLevel int `json:"light_level"`
+}
+
+type Light struct {
+	// LightLevel is deprecated by the bridge in favour of LightLevelReport.
+	LightLevel       int               `json:"light_level"`
+	LightLevelValid  bool              `json:"light_level_valid"`
+	LightLevelReport *LightLevelReport `json:"light_level_report,omitempty"`
+}
+
+type Data struct {
+	ID      string           `json:"id"`
+	IDV1    string           `json:"id_v1,omitempty"`
+	Owner   common.Reference `json:"owner"`
+	Enabled bool             `json:"enabled"`
+	Light   Light            `json:"light"`
+	Type    string           `json:"type"`
+}
+
+var (
+	_ common.Identable = &Data{}
+)
+
+func (d Data) Identity() string {
+	return d.ID
+}
+
+// Level returns the latest raw light level, falling back to the deprecated field on older firmware. ok is false when
+// the sensor has no valid reading.
+func (d Data) Level() (value int, ok bool) {
+	if !d.Light.LightLevelValid {
+		return 0, false
+	}
+	if d.Light.LightLevelReport != nil {
+		return d.Light.LightLevelReport.LightLevel, true
+	}
+	return d.Light.LightLevel, true
+}
+
+// Lux returns the latest reading converted to lux.
+func (d Data) Lux() (value float64, ok bool) {
+	level, ok := d.Level()
+	if !ok {
+		return 0, false
+	}
+	return ToLux(level), true
+}
+
+// Changed returns when the light level was last reported, or the zero time if the bridge did not say.
+func (d Data) Changed() time.Time {
+	if d.Light.LightLevelReport == nil {
+		return time.Time{}
+	}
+	return d.Light.LightLevelReport.Changed
+}
+
+// ToLux converts a raw light level to lux using the documented formula lux = 10^((level-1)/10000).
+func ToLux(level int) float64 {
+	return math.Pow(10, float64(level-1)/10000)
+}
+
+// FromLux converts lux to the raw light level, as used by behaviour dark thresholds.
+func FromLux(lux float64) int {
+	if lux <= 0 {
+		return 0
+	}
+	return int(math.Round(10000*math.Log10(lux))) + 1
+}
+
+type UpdateRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+type Service interface {
+	GetAllLightLevels(ctx context.Context) (*common.ResourceList[Data], error)
+	GetLightLevel(ctx context.Context, id string) (*Data, error)
+	UpdateLightLevel(ctx context.Context, id string, update UpdateRequest) (*common.Reference, error)
+}
@@ -0,0 +1,44 @@
+package light_level
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestToLux(t *testing.T) {
+	tests := []struct {
+		level int
+		lux   float64
+	}{
+		{1, 1},
+		{10001, 10},
+		{20001, 100},
+		{0, 0.99977},
+	}
+	for _, tt := range tests {
+		if got := ToLux(tt.level); math.Abs(got-tt.lux) > 0.001 {
+			t.Errorf("ToLux(%d) = %f, want %f", tt.level, got, tt.lux)
+		}
+		if tt.level > 0 {
+			if got := FromLux(tt.lux); got != tt.level {
+				t.Errorf("FromLux(%f) = %d, want %d", tt.lux, got, tt.level)
+			}
+		}
+	}
+}
+
+func TestData_Lux(t *testing.T) {
+	var d Data
+	body := `{"id":"1","enabled":true,"light":{"light_level":1,"light_level_valid":true,"light_level_report":{"changed":"2024-01-01T10:00:00.000Z","light_level":20001}}}`
+	if err := json.Unmarshal([]byte(body), &d); err != nil {
+		t.Fatal(err)
+	}
+	if lux, ok := d.Lux(); !ok || math.Abs(lux-100) > 0.001 {
+		t.Errorf("Lux() = %f, %v, want 100, true", lux, ok)
+	}
+	d.Light.LightLevelValid = false
+	if _, ok := d.Lux(); ok {
+		t.Errorf("Lux() ok = true for invalid reading")
+	}
+}
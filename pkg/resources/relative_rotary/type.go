@@ -0,0 +1,84 @@
+package relative_rotary
+
+import (
+	"context"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"time"
+)
+
+type Action string
+
+const (
+	Start  Action = "start"
+	Repeat Action = "repeat"
+)
+
+type Direction string
+
+const (
+	ClockWise        Direction = "clock_wise"
+	CounterClockWise Direction = "counter_clock_wise"
+)
+
+type Rotation struct {
+	Direction Direction `json:"direction"`
+	// Steps is the amount rotated since the previous event.
+	Steps int `json:"steps"`
+	// Duration is the time, in milliseconds, the rotation took.
+	Duration int `json:"duration"`
+}
+
+type Event struct {
+	Action   Action   `json:"action"`
+	Rotation Rotation `json:"rotation"`
+}
+
+type Report struct {
+	Updated  time.Time `json:"updated"`
+	Action   Action    `json:"action"`
+	Rotation Rotation  `json:"rotation"`
+}
+
+type RelativeRotary struct {
+	// LastEvent is deprecated by the bridge in favour of RotaryReport.
+	LastEvent    *Event  `json:"last_event,omitempty"`
+	RotaryReport *Report `json:"rotary_report,omitempty"`
+}
+
+type Data struct {
+	ID             string           `json:"id"`
+	IDV1           string           `json:"id_v1,omitempty"`
+	Owner          common.Reference `json:"owner"`
+	RelativeRotary RelativeRotary   `json:"relative_rotary"`
+	Type           string           `json:"type"`
+}
+
+var (
+	_ common.Identable = &Data{}
+)
+
+func (d Data) Identity() string {
+	return d.ID
+}
+
+// LastEvent returns the most recent rotation and when it happened, or nil if the dial has not been turned. The time is
+// zero on firmware that only reports last_event.
+func (d Data) LastEvent() (*Event, time.Time) {
+	if r := d.RelativeRotary.RotaryReport; r != nil {
+		return &Event{Action: r.Action, Rotation: r.Rotation}, r.Updated
+	}
+	return d.RelativeRotary.LastEvent, time.Time{}
+}
+
+// SignedSteps returns the steps of the rotation, negative when turned counter-clockwise.
+func (r Rotation) SignedSteps() int {
+	if r.Direction == CounterClockWise {
+		return -r.Steps
+	}
+	return r.Steps
+}
+
+type Service interface {
+	GetAllRelativeRotaries(ctx context.Context) (*common.ResourceList[Data], error)
+	GetRelativeRotary(ctx context.Context, id string) (*Data, error)
+}
@@ -28,8 +28,8 @@ func (d RoomData) Identity() string {
 
 type RoomUpdate struct {
 	ID       string              `json:"id"`
-	Children *[]common.Reference `json:"children"`
-	Metadata *RoomMetadata       `json:"metadata"`
+	Children *[]common.Reference `json:"children,omitempty"`
+	Metadata *RoomMetadata       `json:"metadata,omitempty"`
 }
 
 type RoomCreate struct {
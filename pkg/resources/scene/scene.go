@@ -7,12 +7,25 @@ import (
 	"time"
 )
 
-// TODO: Implement
+// LightEffect is a legacy effect entry in a scene palette.
 type LightEffect struct {
+	Effect string `json:"effect"`
+}
+
+// LightEffectParameters tune an effect. Speed ranges from 0 to 1.
+type LightEffectParameters struct {
+	Color            *light.Color            `json:"color,omitempty"`
+	ColorTemperature *light.ColorTemperature `json:"color_temperature,omitempty"`
+	Speed            *float64                `json:"speed,omitempty"`
+}
+
+type LightEffectV2Action struct {
+	Effect     string                 `json:"effect"`
+	Parameters *LightEffectParameters `json:"parameters,omitempty"`
 }
 
-// TODO: Implement
 type LightEffectV2 struct {
+	Action LightEffectV2Action `json:"action"`
 }
 
 type AutoGenerated struct {
@@ -27,9 +40,7 @@ type On struct {
 	On bool `json:"on"`
 }
 
-type ColorGradient struct {
-	Points []any
-}
+type ColorGradient = light.Gradient
 type Action struct {
 	On               *On                     `json:"on"`
 	Dimming          *common.Dimming         `json:"dimming"`
@@ -43,14 +54,41 @@ type ActionTarget struct {
 	Action Action `json:"action"`
 }
 
+type PaletteColor struct {
+	Color   light.Color     `json:"color"`
+	Dimming *common.Dimming `json:"dimming,omitempty"`
+}
+
+type PaletteColorTemperature struct {
+	ColorTemperature light.ColorTemperature `json:"color_temperature"`
+	Dimming          *common.Dimming        `json:"dimming,omitempty"`
+}
+
+// Palette holds the colours and effects a dynamic scene cycles through.
 type Palette struct {
-	Color            []light.Color            `json:"color"`
-	Dimming          []common.Dimming         `json:"dimming"`
-	ColorTemperature []light.ColorTemperature `json:"color_temperature"`
-	Effects          []LightEffect            `json:"effects"`
-	EffectsV2        []LightEffectV2          `json:"effects_v2"`
+	Color            []PaletteColor            `json:"color,omitempty"`
+	Dimming          []common.Dimming          `json:"dimming,omitempty"`
+	ColorTemperature []PaletteColorTemperature `json:"color_temperature,omitempty"`
+	Effects          []LightEffect             `json:"effects,omitempty"`
+	EffectsV2        []LightEffectV2           `json:"effects_v2,omitempty"`
+}
+
+// IsDynamic reports whether the palette has anything to animate.
+func (p Palette) IsDynamic() bool {
+	return len(p.Color) > 0 || len(p.ColorTemperature) > 0 || len(p.Effects) > 0 || len(p.EffectsV2) > 0
 }
+
+const (
+	RecallActionActive         = "active"
+	RecallActionDynamicPalette = "dynamic_palette"
+	RecallActionStatic         = "static"
+)
+
+// Recall is sent on update to activate a scene. Duration is in milliseconds.
 type Recall struct {
+	Action   string          `json:"action,omitempty"`
+	Duration *int            `json:"duration,omitempty"`
+	Dimming  *common.Dimming `json:"dimming,omitempty"`
 }
 type Image struct {
 	Rid   string `json:"rid"`
@@ -87,14 +125,22 @@ func (s SceneData) Identity() string {
 var _ common.Identable = &SceneData{}
 
 type SceneCreate struct {
-	Metadata SceneMetadata    `json:"metadata"`
-	Actions  []ActionTarget   `json:"actions"`
-	Group    common.Reference `json:"group"`
+	Metadata    SceneMetadata    `json:"metadata"`
+	Actions     []ActionTarget   `json:"actions"`
+	Group       common.Reference `json:"group"`
+	Palette     *Palette         `json:"palette,omitempty"`
+	Speed       *float64         `json:"speed,omitempty"`
+	AutoDynamic *bool            `json:"auto_dynamic,omitempty"`
 }
 
+// SceneUpdate only sends the fields that are set.
 type SceneUpdate struct {
-	Metadata SceneMetadata  `json:"metadata"`
-	Actions  []ActionTarget `json:"actions"`
+	Metadata    *SceneMetadata `json:"metadata,omitempty"`
+	Actions     []ActionTarget `json:"actions,omitempty"`
+	Palette     *Palette       `json:"palette,omitempty"`
+	Speed       *float64       `json:"speed,omitempty"`
+	AutoDynamic *bool          `json:"auto_dynamic,omitempty"`
+	Recall      *Recall        `json:"recall,omitempty"`
 }
 
 type SceneService interface {
@@ -103,4 +149,11 @@ type SceneService interface {
 	UpdateScene(ctx context.Context, id string, scene SceneUpdate) (*common.Reference, error)
 	CreateScene(ctx context.Context, scene SceneCreate) (*common.Reference, error)
 	DeleteScene(ctx context.Context, id string) error
+	// RecallScene activates the scene. Use RecallActionDynamicPalette to start a dynamic scene animating.
+	RecallScene(ctx context.Context, id string, recall Recall) (*common.Reference, error)
+	// SnapshotRoomToScene builds a SceneCreate from the current state of every light in the room or zone.
+	SnapshotRoomToScene(ctx context.Context, groupID string, name string) (*SceneCreate, error)
+	// ValidateScene checks the scene against its group and the capabilities of each light, returning a
+	// *ValidationError listing every problem.
+	ValidateScene(ctx context.Context, scene SceneCreate) error
 }
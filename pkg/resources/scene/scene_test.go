@@ -0,0 +1,97 @@
+package scene
+
+import (
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"github.com/richseviora/huego/pkg/resources/color"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/light"
+	"testing"
+)
+
+var paletteResponse = `{
+    "color": [
+        {
+            "color": {"xy": {"x": 0.5, "y": 0.4}},
+            "dimming": {"brightness": 80.0}
+        }
+    ],
+    "dimming": [],
+    "color_temperature": [
+        {
+            "color_temperature": {"mirek": 346},
+            "dimming": {"brightness": 100.0}
+        }
+    ],
+    "effects": [
+        {"effect": "candle"}
+    ],
+    "effects_v2": [
+        {
+            "action": {
+                "effect": "fire",
+                "parameters": {
+                    "color": {"xy": {"x": 0.6, "y": 0.3}},
+                    "speed": 0.5
+                }
+            }
+        }
+    ]
+}`
+
+func TestPalette_UnmarshalJSON(t *testing.T) {
+	speed := 0.5
+	expected := Palette{
+		Color: []PaletteColor{
+			{Color: light.Color{XY: color.XYCoord{X: 0.5, Y: 0.4}}, Dimming: &common.Dimming{Brightness: 80}},
+		},
+		Dimming: []common.Dimming{},
+		ColorTemperature: []PaletteColorTemperature{
+			{ColorTemperature: light.ColorTemperature{Mirek: 346}, Dimming: &common.Dimming{Brightness: 100}},
+		},
+		Effects: []LightEffect{{Effect: "candle"}},
+		EffectsV2: []LightEffectV2{
+			{Action: LightEffectV2Action{
+				Effect: "fire",
+				Parameters: &LightEffectParameters{
+					Color: &light.Color{XY: color.XYCoord{X: 0.6, Y: 0.3}},
+					Speed: &speed,
+				},
+			}},
+		},
+	}
+	var result Palette
+	if err := json.Unmarshal([]byte(paletteResponse), &result); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+	if !result.IsDynamic() {
+		t.Errorf("IsDynamic() = false, want true")
+	}
+}
+
+func TestSceneUpdate_MarshalJSON(t *testing.T) {
+	speed := 0.25
+	autoDynamic := true
+	testCases := []struct {
+		name     string
+		update   SceneUpdate
+		expected string
+	}{
+		{"only sends speed and auto_dynamic", SceneUpdate{Speed: &speed, AutoDynamic: &autoDynamic}, `{"speed":0.25,"auto_dynamic":true}`},
+		{"sends dynamic recall", SceneUpdate{Recall: &Recall{Action: RecallActionDynamicPalette}}, `{"recall":{"action":"dynamic_palette"}}`},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := json.Marshal(tt.update)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(result) != tt.expected {
+				t.Errorf("json.Marshal() = %v, want %v", string(result), tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package scene
+
+import (
+	"fmt"
+	"github.com/richseviora/huego/pkg/resources/light"
+	"strings"
+)
+
+// ValidationProblem describes a single issue with a scene. LightID is empty for scene level problems.
+type ValidationProblem struct {
+	LightID string
+	Message string
+}
+
+func (p ValidationProblem) String() string {
+	if p.LightID == "" {
+		return p.Message
+	}
+	return fmt.Sprintf("light %s: %s", p.LightID, p.Message)
+}
+
+// ValidationError is returned when a scene fails validation, it holds every problem found.
+type ValidationError struct {
+	Problems []ValidationProblem
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		messages[i] = p.String()
+	}
+	return fmt.Sprintf("scene is invalid: %s", strings.Join(messages, "; "))
+}
+
+// ValidateActions checks the actions against the lights in the scene's group. groupLightIDs are the IDs of every
+// light in the room or zone, lights must contain at least those lights. A nil error means no problems were found,
+// otherwise a *ValidationError is returned.
+func ValidateActions(actions []ActionTarget, groupLightIDs []string, lights []light.Light) error {
+	var problems []ValidationProblem
+	lightsByID := make(map[string]light.Light, len(lights))
+	for _, l := range lights {
+		lightsByID[l.ID] = l
+	}
+	inGroup := make(map[string]bool, len(groupLightIDs))
+	for _, id := range groupLightIDs {
+		inGroup[id] = true
+	}
+
+	seen := make(map[string]bool, len(actions))
+	for _, a := range actions {
+		id := a.Target.Rid
+		if a.Target.Rtype != "light" {
+			problems = append(problems, ValidationProblem{LightID: id, Message: fmt.Sprintf("target type %q is not a light", a.Target.Rtype)})
+			continue
+		}
+		if seen[id] {
+			problems = append(problems, ValidationProblem{LightID: id, Message: "has more than one action"})
+			continue
+		}
+		seen[id] = true
+		if !inGroup[id] {
+			problems = append(problems, ValidationProblem{LightID: id, Message: "is not in the scene's group"})
+		}
+		l, ok := lightsByID[id]
+		if !ok {
+			problems = append(problems, ValidationProblem{LightID: id, Message: "not found"})
+			continue
+		}
+		problems = append(problems, validateAction(l, a.Action)...)
+	}
+
+	for _, id := range groupLightIDs {
+		if !seen[id] {
+			problems = append(problems, ValidationProblem{LightID: id, Message: "has no action"})
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: problems}
+}
+
+func validateAction(l light.Light, a Action) []ValidationProblem {
+	var problems []ValidationProblem
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, ValidationProblem{LightID: l.ID, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if a.Dimming != nil {
+		switch {
+		case !l.SupportsDimming():
+			add("does not support dimming")
+		case a.Dimming.Brightness < 0 || a.Dimming.Brightness > 100:
+			add("brightness %v is outside 0-100", a.Dimming.Brightness)
+		case l.Dimming.MinDimLevel != nil && a.Dimming.Brightness < *l.Dimming.MinDimLevel:
+			add("brightness %v is below the minimum dim level %v", a.Dimming.Brightness, *l.Dimming.MinDimLevel)
+		}
+	}
+	if a.Color != nil {
+		switch {
+		case !l.SupportsColor():
+			add("does not support colour")
+		case !l.Color.Gamut.Contains(a.Color.XY):
+			add("colour %v is outside gamut %s", a.Color.XY, l.Color.GamutType)
+		}
+	}
+	if a.ColorTemperature != nil {
+		switch {
+		case !l.SupportsColorTemperature():
+			add("does not support colour temperature")
+		case !inMirekRange(l.ColorTemp, a.ColorTemperature.Mirek):
+			schema := l.ColorTemp.MirekSchema
+			add("mirek %d is outside %d-%d", a.ColorTemperature.Mirek, schema.MirekMinimum, schema.MirekMaximum)
+		}
+	}
+	if a.Color != nil && a.ColorTemperature != nil {
+		add("sets both colour and colour temperature")
+	}
+	if a.Gradient != nil {
+		switch {
+		case !l.SupportsGradient():
+			add("does not support gradients")
+		case len(a.Gradient.Points) > l.Gradient.PointsCapable:
+			add("gradient has %d points, light supports %d", len(a.Gradient.Points), l.Gradient.PointsCapable)
+		default:
+			for _, p := range a.Gradient.Points {
+				if l.SupportsColor() && !l.Color.Gamut.Contains(p.Color.XY) {
+					add("gradient colour %v is outside gamut %s", p.Color.XY, l.Color.GamutType)
+				}
+			}
+		}
+	}
+	if a.EffectsV2 != nil && !supportsEffect(l, a.EffectsV2.Action.Effect) {
+		add("does not support effect %q", a.EffectsV2.Action.Effect)
+	}
+	return problems
+}
+
+// inMirekRange checks the mirek value against the light's schema, if it reported one.
+func inMirekRange(ct *light.ColorTemperatureInfo, mirek int) bool {
+	schema := ct.MirekSchema
+	if schema.MirekMaximum == 0 {
+		return true
+	}
+	return mirek >= schema.MirekMinimum && mirek <= schema.MirekMaximum
+}
+
+func supportsEffect(l light.Light, effect string) bool {
+	if effect == light.NoEffect {
+		return true
+	}
+	if l.EffectsV2 == nil {
+		return false
+	}
+	for _, v := range l.EffectsV2.Action.EffectValues {
+		if v == effect {
+			return true
+		}
+	}
+	return false
+}
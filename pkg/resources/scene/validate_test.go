@@ -0,0 +1,105 @@
+package scene
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/richseviora/huego/pkg/resources/color"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/light"
+	"testing"
+)
+
+func TestValidateActions(t *testing.T) {
+	colorMinDimLevel, ambianceMinDimLevel := 0.2, 1.0
+	colorLight := light.Light{
+		ID:      "color",
+		Dimming: &light.DimmingInfo{Brightness: 100, MinDimLevel: &colorMinDimLevel},
+		Color: &light.ColorInfo{
+			GamutType: "C",
+			Gamut: light.ColorGamut{
+				Red:   color.XYCoord{X: 0.6915, Y: 0.3083},
+				Green: color.XYCoord{X: 0.17, Y: 0.7},
+				Blue:  color.XYCoord{X: 0.1532, Y: 0.0475},
+			},
+		},
+		ColorTemp: &light.ColorTemperatureInfo{},
+	}
+	colorLight.ColorTemp.MirekSchema.MirekMinimum = 153
+	colorLight.ColorTemp.MirekSchema.MirekMaximum = 500
+
+	ambianceLight := light.Light{
+		ID:        "ambiance",
+		Dimming:   &light.DimmingInfo{Brightness: 100, MinDimLevel: &ambianceMinDimLevel},
+		ColorTemp: &light.ColorTemperatureInfo{},
+	}
+	ambianceLight.ColorTemp.MirekSchema.MirekMinimum = 153
+	ambianceLight.ColorTemp.MirekSchema.MirekMaximum = 454
+
+	// A light that is off can report a brightness of 0, and min_dim_level is optional.
+	var whiteLight light.Light
+	if err := json.Unmarshal([]byte(`{"id":"white","dimming":{"brightness":0}}`), &whiteLight); err != nil {
+		t.Fatal(err)
+	}
+	plugLight := light.Light{ID: "plug"}
+
+	lights := []light.Light{colorLight, ambianceLight, whiteLight, plugLight}
+	groupLightIDs := []string{"color", "ambiance", "white", "plug"}
+	action := func(id string, a Action) ActionTarget {
+		return ActionTarget{Target: Target{Rid: id, Rtype: "light"}, Action: a}
+	}
+
+	testCases := []struct {
+		name     string
+		actions  []ActionTarget
+		expected []ValidationProblem
+	}{
+		{"accepts valid actions", []ActionTarget{
+			action("color", Action{Color: &light.Color{XY: color.XYCoord{X: 0.4, Y: 0.4}}}),
+			action("ambiance", Action{ColorTemperature: &light.ColorTemperature{Mirek: 366}, Dimming: &common.Dimming{Brightness: 50}}),
+			action("white", Action{Dimming: &common.Dimming{Brightness: 0.5}}),
+			action("plug", Action{On: &On{On: true}}),
+		}, nil},
+		{"reports every problem", []ActionTarget{
+			action("color", Action{Color: &light.Color{XY: color.XYCoord{X: 0.05, Y: 0.9}}}),
+			action("ambiance", Action{Color: &light.Color{XY: color.XYCoord{X: 0.4, Y: 0.4}}, Dimming: &common.Dimming{Brightness: 0.5}}),
+			action("other", Action{}),
+			action("white", Action{ColorTemperature: &light.ColorTemperature{Mirek: 366}}),
+			action("plug", Action{Dimming: &common.Dimming{Brightness: 50}}),
+		}, []ValidationProblem{
+			{LightID: "color", Message: "colour {0.05 0.9} is outside gamut C"},
+			{LightID: "ambiance", Message: "brightness 0.5 is below the minimum dim level 1"},
+			{LightID: "ambiance", Message: "does not support colour"},
+			{LightID: "other", Message: "is not in the scene's group"},
+			{LightID: "other", Message: "not found"},
+			{LightID: "white", Message: "does not support colour temperature"},
+			{LightID: "plug", Message: "does not support dimming"},
+		}},
+		{"reports mirek out of range and missing lights", []ActionTarget{
+			action("ambiance", Action{ColorTemperature: &light.ColorTemperature{Mirek: 500}}),
+		}, []ValidationProblem{
+			{LightID: "ambiance", Message: "mirek 500 is outside 153-454"},
+			{LightID: "color", Message: "has no action"},
+			{LightID: "white", Message: "has no action"},
+			{LightID: "plug", Message: "has no action"},
+		}},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateActions(tt.actions, groupLightIDs, lights)
+			if tt.expected == nil {
+				if err != nil {
+					t.Errorf("ValidateActions() error = %v, want nil", err)
+				}
+				return
+			}
+			var validationError *ValidationError
+			if !errors.As(err, &validationError) {
+				t.Fatalf("ValidateActions() error = %v, want *ValidationError", err)
+			}
+			if diff := cmp.Diff(tt.expected, validationError.Problems); diff != "" {
+				t.Errorf("Mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
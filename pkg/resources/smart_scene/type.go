@@ -0,0 +1,119 @@
+package smart_scene
+
+import (
+	"context"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/scene"
+)
+
+type Weekday string
+
+const (
+	Monday    Weekday = "monday"
+	Tuesday   Weekday = "tuesday"
+	Wednesday Weekday = "wednesday"
+	Thursday  Weekday = "thursday"
+	Friday    Weekday = "friday"
+	Saturday  Weekday = "saturday"
+	Sunday    Weekday = "sunday"
+)
+
+// AllWeek is a convenience recurrence for timeslots that apply every day.
+var AllWeek = []Weekday{Monday, Tuesday, Wednesday, Thursday, Friday, Saturday, Sunday}
+
+type StartTimeKind string
+
+const (
+	// StartTimeKindTime starts the timeslot at a fixed time of day.
+	StartTimeKindTime StartTimeKind = "time"
+	// StartTimeKindSunset starts the timeslot at sunset, the Time field is ignored by the bridge.
+	StartTimeKindSunset StartTimeKind = "sunset"
+)
+
+type Time struct {
+	Hour   int `json:"hour"`
+	Minute int `json:"minute"`
+	Second int `json:"second"`
+}
+
+type StartTime struct {
+	Kind StartTimeKind `json:"kind"`
+	Time *Time         `json:"time,omitempty"`
+}
+
+// Timeslot recalls the target scene at the start time.
+type Timeslot struct {
+	StartTime StartTime        `json:"start_time"`
+	Target    common.Reference `json:"target"`
+}
+
+type WeekTimeslots struct {
+	Timeslots  []Timeslot `json:"timeslots"`
+	Recurrence []Weekday  `json:"recurrence"`
+}
+
+type ActiveTimeslot struct {
+	// TimeslotID is the index of the active timeslot within the week timeslots for Weekday.
+	TimeslotID int     `json:"timeslot_id"`
+	Weekday    Weekday `json:"weekday"`
+}
+
+const (
+	StateActive   = "active"
+	StateInactive = "inactive"
+)
+
+const (
+	RecallActionActivate   = "activate"
+	RecallActionDeactivate = "deactivate"
+)
+
+type Recall struct {
+	Action string `json:"action"`
+}
+
+type Data struct {
+	ID       string              `json:"id"`
+	IDV1     string              `json:"id_v1,omitempty"`
+	Type     string              `json:"type"`
+	Metadata scene.SceneMetadata `json:"metadata"`
+	Group    common.Reference    `json:"group"`
+	// TransitionDuration is in milliseconds.
+	TransitionDuration int             `json:"transition_duration"`
+	WeekTimeslots      []WeekTimeslots `json:"week_timeslots"`
+	ActiveTimeslot     *ActiveTimeslot `json:"active_timeslot,omitempty"`
+	State              string          `json:"state"`
+}
+
+var (
+	_ common.Identable = &Data{}
+)
+
+func (d Data) Identity() string {
+	return d.ID
+}
+
+type CreateRequest struct {
+	Metadata           scene.SceneMetadata `json:"metadata"`
+	Group              common.Reference    `json:"group"`
+	WeekTimeslots      []WeekTimeslots     `json:"week_timeslots"`
+	TransitionDuration *int                `json:"transition_duration,omitempty"`
+	Recall             *Recall             `json:"recall,omitempty"`
+}
+
+type UpdateRequest struct {
+	Metadata           *scene.SceneMetadata `json:"metadata,omitempty"`
+	WeekTimeslots      []WeekTimeslots      `json:"week_timeslots,omitempty"`
+	TransitionDuration *int                 `json:"transition_duration,omitempty"`
+	Recall             *Recall              `json:"recall,omitempty"`
+}
+
+type Service interface {
+	GetAllSmartScenes(ctx context.Context) (*common.ResourceList[Data], error)
+	GetSmartScene(ctx context.Context, id string) (*Data, error)
+	CreateSmartScene(ctx context.Context, create CreateRequest) (*common.Reference, error)
+	UpdateSmartScene(ctx context.Context, id string, update UpdateRequest) (*common.Reference, error)
+	DeleteSmartScene(ctx context.Context, id string) error
+	ActivateSmartScene(ctx context.Context, id string) (*common.Reference, error)
+	DeactivateSmartScene(ctx context.Context, id string) (*common.Reference, error)
+}
@@ -0,0 +1,63 @@
+package tamper
+
+import (
+	"context"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"time"
+)
+
+type State string
+
+const (
+	Tampered    State = "tampered"
+	NotTampered State = "not_tampered"
+)
+
+type Report struct {
+	Changed time.Time `json:"changed"`
+	// Source is the part that was tampered with, such as "battery_door".
+	Source string `json:"source"`
+	State  State  `json:"state"`
+}
+
+type Data struct {
+	ID            string           `json:"id"`
+	IDV1          string           `json:"id_v1,omitempty"`
+	Owner         common.Reference `json:"owner"`
+	TamperReports []Report         `json:"tamper_reports"`
+	Type          string           `json:"type"`
+}
+
+var (
+	_ common.Identable = &Data{}
+)
+
+func (d Data) Identity() string {
+	return d.ID
+}
+
+// IsTampered reports whether any source is currently tampered.
+func (d Data) IsTampered() bool {
+	for _, r := range d.TamperReports {
+		if r.State == Tampered {
+			return true
+		}
+	}
+	return false
+}
+
+// LastChanged returns the most recent change across all sources, or the zero time if there are no reports.
+func (d Data) LastChanged() time.Time {
+	var result time.Time
+	for _, r := range d.TamperReports {
+		if r.Changed.After(result) {
+			result = r.Changed
+		}
+	}
+	return result
+}
+
+type Service interface {
+	GetAllTampers(ctx context.Context) (*common.ResourceList[Data], error)
+	GetTamper(ctx context.Context, id string) (*Data, error)
+}
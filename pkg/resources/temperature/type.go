@@ -0,0 +1,80 @@
+package temperature
+
+import (
+	"context"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"time"
+)
+
+type TemperatureReport struct {
+	Changed time.Time `json:"changed"`
+	// Temperature is in degrees Celsius.
+	Temperature float64 `json:"temperature"`
+}
+
+type Temperature struct {
+	// Temperature is deprecated by the bridge in favour of TemperatureReport.
+	Temperature       float64            `json:"temperature"`
+	TemperatureValid  bool               `json:"temperature_valid"`
+	TemperatureReport *TemperatureReport `json:"temperature_report,omitempty"`
+}
+
+type Data struct {
+	ID          string           `json:"id"`
+	IDV1        string           `json:"id_v1,omitempty"`
+	Owner       common.Reference `json:"owner"`
+	Enabled     bool             `json:"enabled"`
+	Temperature Temperature      `json:"temperature"`
+	Type        string           `json:"type"`
+}
+
+var (
+	_ common.Identable = &Data{}
+)
+
+func (d Data) Identity() string {
+	return d.ID
+}
+
+// Celsius returns the latest reported temperature, falling back to the deprecated field on older firmware. ok is false
+// when the sensor has no valid reading.
+func (d Data) Celsius() (value float64, ok bool) {
+	if !d.Temperature.TemperatureValid {
+		return 0, false
+	}
+	if d.Temperature.TemperatureReport != nil {
+		return d.Temperature.TemperatureReport.Temperature, true
+	}
+	return d.Temperature.Temperature, true
+}
+
+// Fahrenheit returns the latest reported temperature in degrees Fahrenheit.
+func (d Data) Fahrenheit() (value float64, ok bool) {
+	c, ok := d.Celsius()
+	if !ok {
+		return 0, false
+	}
+	return CelsiusToFahrenheit(c), true
+}
+
+// Changed returns when the temperature was last reported, or the zero time if the bridge did not say.
+func (d Data) Changed() time.Time {
+	if d.Temperature.TemperatureReport == nil {
+		return time.Time{}
+	}
+	return d.Temperature.TemperatureReport.Changed
+}
+
+func CelsiusToFahrenheit(c float64) float64 {
+	return c*9/5 + 32
+}
+
+type UpdateRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+type Service interface {
+	GetAllTemperatures(ctx context.Context) (*common.ResourceList[Data], error)
+	GetTemperature(ctx context.Context, id string) (*Data, error)
+	UpdateTemperature(ctx context.Context, id string, update UpdateRequest) (*common.Reference, error)
+}
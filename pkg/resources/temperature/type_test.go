@@ -0,0 +1,23 @@
+package temperature
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestData_Fahrenheit(t *testing.T) {
+	var d Data
+	body := `{"id":"1","enabled":true,"temperature":{"temperature":19.5,"temperature_valid":true,"temperature_report":{"changed":"2024-01-01T10:00:00.000Z","temperature":20}}}`
+	if err := json.Unmarshal([]byte(body), &d); err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := d.Celsius(); !ok || c != 20 {
+		t.Errorf("Celsius() = %f, %v, want 20, true", c, ok)
+	}
+	if f, ok := d.Fahrenheit(); !ok || f != 68 {
+		t.Errorf("Fahrenheit() = %f, %v, want 68, true", f, ok)
+	}
+	if d.Changed().IsZero() {
+		t.Errorf("Changed() is zero")
+	}
+}
@@ -5,6 +5,24 @@ import (
 	"github.com/richseviora/huego/pkg/resources/common"
 )
 
+const (
+	StatusConnected              = "connected"
+	StatusDisconnected           = "disconnected"
+	StatusConnectivityIssue      = "connectivity_issue"
+	StatusUnidirectionalIncoming = "unidirectional_incoming"
+)
+
+// Channels the bridge can form its Zigbee network on.
+const (
+	Channel11            = "channel_11"
+	Channel15            = "channel_15"
+	Channel20            = "channel_20"
+	Channel25            = "channel_25"
+	ChannelNotConfigured = "not_configured"
+)
+
+var Channels = []string{Channel11, Channel15, Channel20, Channel25}
+
 type Channel struct {
 	Status string `json:"status"`
 	Value  string `json:"value"`
@@ -22,6 +40,21 @@ type Data struct {
 
 var _ common.Identable = &Data{}
 
+// IsHealthy reports whether the device is connected in both directions.
+func (d Data) IsHealthy() bool {
+	return d.Status == StatusConnected
+}
+
+type ChannelUpdate struct {
+	Value string `json:"value"`
+}
+
+// UpdateRequest changes the Zigbee channel. Only the bridge's own zigbee_connectivity accepts it, and every device has
+// to follow the bridge to the new channel, which can take several minutes.
+type UpdateRequest struct {
+	Channel *ChannelUpdate `json:"channel,omitempty"`
+}
+
 func (d Data) Identity() string {
 	return d.ID
 }
@@ -29,4 +62,5 @@ func (d Data) Identity() string {
 type Service interface {
 	GetAllZigbeeConnectivity(ctx context.Context) (*common.ResourceList[Data], error)
 	GetZigbeeConnectivity(ctx context.Context, id string) (*Data, error)
+	UpdateZigbeeConnectivity(ctx context.Context, id string, update UpdateRequest) (*common.Reference, error)
 }
@@ -0,0 +1,58 @@
+package zigbee_device_discovery
+
+import (
+	"context"
+	"github.com/richseviora/huego/pkg/resources/common"
+)
+
+type Status string
+
+const (
+	// Active means the bridge is searching for new devices.
+	Active Status = "active"
+	Ready  Status = "ready"
+)
+
+const ActionSearch = "search"
+
+type Action struct {
+	ActionTypeValues []string `json:"action_type_values"`
+}
+
+type Data struct {
+	ID     string           `json:"id"`
+	Owner  common.Reference `json:"owner"`
+	Status Status           `json:"status"`
+	Action Action           `json:"action"`
+	Type   string           `json:"type"`
+}
+
+var (
+	_ common.Identable = &Data{}
+)
+
+func (d Data) Identity() string {
+	return d.ID
+}
+
+type SearchAction struct {
+	ActionType string `json:"action_type"`
+	// SearchCodes are the serial numbers printed on bulbs, used to find lights that were reset or belong to another
+	// bridge. At most 10 are accepted.
+	SearchCodes []string `json:"search_codes,omitempty"`
+	// InstallCodes are the install codes of devices that require one to join.
+	InstallCodes []string `json:"install_codes,omitempty"`
+}
+
+type UpdateRequest struct {
+	Action SearchAction `json:"action"`
+}
+
+// MaxSearchCodes is the number of serial numbers the bridge accepts in one search.
+const MaxSearchCodes = 10
+
+type Service interface {
+	GetAllZigbeeDeviceDiscovery(ctx context.Context) (*common.ResourceList[Data], error)
+	GetZigbeeDeviceDiscovery(ctx context.Context, id string) (*Data, error)
+	UpdateZigbeeDeviceDiscovery(ctx context.Context, id string, update UpdateRequest) (*common.Reference, error)
+}
@@ -8,8 +8,8 @@ import (
 type ZoneResponse = common.ResourceList[ZoneData]
 
 type ZoneMetadata struct {
-	Name      string `json:"name"`
-	Archetype string `json:"archetype"`
+	Name      string      `json:"name"`
+	Archetype common.Area `json:"archetype"`
 }
 
 type ZoneData struct {
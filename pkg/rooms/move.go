@@ -0,0 +1,293 @@
+package rooms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/richseviora/huego/pkg/resources/client"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/room"
+	"github.com/richseviora/huego/pkg/resources/scene"
+)
+
+// DefaultMaxAttempts is the number of times MoveDevice will try the read-modify-write of both rooms.
+const DefaultMaxAttempts = 3
+
+// ErrConcurrentModification is returned when the rooms did not hold the expected children after every attempt.
+var ErrConcurrentModification = errors.New("room was modified concurrently")
+
+type moveOptions struct {
+	cleanupScenes bool
+	maxAttempts   int
+}
+
+// MoveOption defines functional options for MoveDevice.
+type MoveOption func(*moveOptions)
+
+// WithSceneCleanup removes the device's lights from scenes in the source room, deleting scenes left without actions.
+func WithSceneCleanup() MoveOption {
+	return func(o *moveOptions) {
+		o.cleanupScenes = true
+	}
+}
+
+// WithMaxAttempts overrides DefaultMaxAttempts.
+func WithMaxAttempts(attempts int) MoveOption {
+	return func(o *moveOptions) {
+		o.maxAttempts = attempts
+	}
+}
+
+type SceneOutcome string
+
+const (
+	// SceneReported means the scene still targets the device's lights.
+	SceneReported SceneOutcome = "reported"
+	// SceneUpdated means the device's lights were removed from the scene.
+	SceneUpdated SceneOutcome = "updated"
+	// SceneDeleted means the scene only targeted the device's lights and was removed.
+	SceneDeleted SceneOutcome = "deleted"
+)
+
+type AffectedScene struct {
+	ID      string
+	Name    string
+	Outcome SceneOutcome
+}
+
+// MoveResult describes what MoveDevice changed. SourceRoomID is empty when the device was not in a room.
+type MoveResult struct {
+	SourceRoomID string
+	Attempts     int
+	Scenes       []AffectedScene
+	// Behaviors are behaviour instances in the source room using the device as their source. They are not modified.
+	Behaviors []common.Reference
+}
+
+// MoveDevice moves the device into the target room, removing it from the room it is currently in. Both rooms are
+// re-read and verified after writing, and the move is retried if another client changed them in the meantime.
+func MoveDevice(ctx context.Context, c client.HueServiceClient, deviceID, targetRoomID string, opts ...MoveOption) (*MoveResult, error) {
+	options := moveOptions{maxAttempts: DefaultMaxAttempts}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	d, err := c.DeviceService().GetDevice(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MoveResult{}
+	var originalSource *room.RoomData
+	var lastErr error
+	moved := false
+	for result.Attempts < options.maxAttempts && !moved {
+		result.Attempts++
+		holders, target, err := findRooms(ctx, c, deviceID, targetRoomID)
+		if err != nil {
+			return nil, err
+		}
+		// A stale write by another client can leave the device in several rooms, so every room other than the
+		// target is treated as a source.
+		var sources []*room.RoomData
+		for _, r := range holders {
+			if r.ID != target.ID {
+				sources = append(sources, r)
+			}
+		}
+		if originalSource == nil && len(sources) > 0 {
+			originalSource = sources[0]
+		} else if originalSource == nil && len(holders) > 0 {
+			originalSource = target
+		}
+		if len(sources) == 0 && len(holders) > 0 {
+			moved = true
+			break
+		}
+		if err := moveOnce(ctx, c, deviceID, sources, target); err != nil {
+			lastErr = err
+			continue
+		}
+		moved, lastErr = verify(ctx, c, deviceID, sources, target)
+	}
+	if !moved {
+		if lastErr == nil {
+			lastErr = ErrConcurrentModification
+		}
+		if originalSource != nil {
+			lastErr = errors.Join(lastErr, restore(ctx, c, deviceID, originalSource.ID))
+		}
+		return result, fmt.Errorf("failed to move device %s after %d attempts: %w", deviceID, result.Attempts, lastErr)
+	}
+	if originalSource == nil || originalSource.ID == targetRoomID {
+		return result, nil
+	}
+	result.SourceRoomID = originalSource.ID
+
+	lightIDs := make(map[string]bool)
+	for _, service := range d.Services {
+		if service.Rtype == "light" {
+			lightIDs[service.Rid] = true
+		}
+	}
+	if err := handleScenes(ctx, c, result, lightIDs, options.cleanupScenes); err != nil {
+		return result, err
+	}
+	behaviors, err := c.BehaviorInstanceService().GetAllBehaviorInstances(ctx)
+	if err != nil {
+		return result, err
+	}
+	for _, b := range behaviors.Data {
+		if b.Configuration.Source.RID != deviceID {
+			continue
+		}
+		for _, where := range b.Configuration.Where {
+			if where.Group.RID == result.SourceRoomID {
+				result.Behaviors = append(result.Behaviors, common.Reference{RID: b.ID, RType: "behavior_instance"})
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// findRooms returns every room currently holding the device and the target room.
+func findRooms(ctx context.Context, c client.HueServiceClient, deviceID, targetRoomID string) ([]*room.RoomData, *room.RoomData, error) {
+	rooms, err := c.RoomService().GetAllRooms(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var holders []*room.RoomData
+	var target *room.RoomData
+	for i := range rooms.Data {
+		r := &rooms.Data[i]
+		if r.ID == targetRoomID {
+			target = r
+		}
+		if containsDevice(r.Children, deviceID) {
+			holders = append(holders, r)
+		}
+	}
+	if target == nil {
+		return nil, nil, fmt.Errorf("room %s not found: %w", targetRoomID, client.ErrNotFound)
+	}
+	return holders, target, nil
+}
+
+// moveOnce removes the device from the sources before adding it to the target, as a device can only be in one room.
+func moveOnce(ctx context.Context, c client.HueServiceClient, deviceID string, sources []*room.RoomData, target *room.RoomData) error {
+	for _, source := range sources {
+		children := withoutDevice(source.Children, deviceID)
+		if err := c.RoomService().UpdateRoom(ctx, room.RoomUpdate{ID: source.ID, Children: &children}); err != nil {
+			return err
+		}
+	}
+	children := append(withoutDevice(target.Children, deviceID), common.Reference{RID: deviceID, RType: "device"})
+	return c.RoomService().UpdateRoom(ctx, room.RoomUpdate{ID: target.ID, Children: &children})
+}
+
+// verify checks the device moved and that every other child seen before writing is still present in the rooms.
+func verify(ctx context.Context, c client.HueServiceClient, deviceID string, sources []*room.RoomData, target *room.RoomData) (bool, error) {
+	updatedTarget, err := c.RoomService().GetRoom(ctx, target.ID)
+	if err != nil {
+		return false, err
+	}
+	if !containsDevice(updatedTarget.Children, deviceID) || !containsAll(updatedTarget.Children, target.Children) {
+		return false, ErrConcurrentModification
+	}
+	for _, source := range sources {
+		updatedSource, err := c.RoomService().GetRoom(ctx, source.ID)
+		if err != nil {
+			return false, err
+		}
+		if containsDevice(updatedSource.Children, deviceID) || !containsAll(updatedSource.Children, withoutDevice(source.Children, deviceID)) {
+			return false, ErrConcurrentModification
+		}
+	}
+	return true, nil
+}
+
+// restore puts the device back in its original room after a failed move, if it ended up without a room.
+func restore(ctx context.Context, c client.HueServiceClient, deviceID, roomID string) error {
+	rooms, err := c.RoomService().GetAllRooms(ctx)
+	if err != nil {
+		return err
+	}
+	for _, r := range rooms.Data {
+		if containsDevice(r.Children, deviceID) {
+			return nil
+		}
+	}
+	original, err := c.RoomService().GetRoom(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	children := append(original.Children, common.Reference{RID: deviceID, RType: "device"})
+	if err := c.RoomService().UpdateRoom(ctx, room.RoomUpdate{ID: roomID, Children: &children}); err != nil {
+		return fmt.Errorf("failed to restore device to room %s: %w", roomID, err)
+	}
+	return nil
+}
+
+func handleScenes(ctx context.Context, c client.HueServiceClient, result *MoveResult, lightIDs map[string]bool, cleanup bool) error {
+	scenes, err := c.SceneService().GetAllScenes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, s := range scenes.Data {
+		if s.Group.RID != result.SourceRoomID {
+			continue
+		}
+		remaining := make([]scene.ActionTarget, 0, len(s.Actions))
+		for _, a := range s.Actions {
+			if !lightIDs[a.Target.Rid] {
+				remaining = append(remaining, a)
+			}
+		}
+		if len(remaining) == len(s.Actions) {
+			continue
+		}
+		affected := AffectedScene{ID: s.ID, Name: s.Metadata.Name, Outcome: SceneReported}
+		if cleanup {
+			if len(remaining) == 0 {
+				err = c.SceneService().DeleteScene(ctx, s.ID)
+				affected.Outcome = SceneDeleted
+			} else {
+				_, err = c.SceneService().UpdateScene(ctx, s.ID, scene.SceneUpdate{Actions: remaining})
+				affected.Outcome = SceneUpdated
+			}
+			if err != nil {
+				return fmt.Errorf("failed to clean up scene %s: %w", s.ID, err)
+			}
+		}
+		result.Scenes = append(result.Scenes, affected)
+	}
+	return nil
+}
+
+func containsDevice(children []common.Reference, deviceID string) bool {
+	for _, child := range children {
+		if child.RID == deviceID {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAll(children, expected []common.Reference) bool {
+	for _, e := range expected {
+		if !containsDevice(children, e.RID) {
+			return false
+		}
+	}
+	return true
+}
+
+func withoutDevice(children []common.Reference, deviceID string) []common.Reference {
+	result := make([]common.Reference, 0, len(children))
+	for _, child := range children {
+		if child.RID != deviceID {
+			result = append(result, child)
+		}
+	}
+	return result
+}
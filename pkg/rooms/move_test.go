@@ -0,0 +1,184 @@
+package rooms
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	client2 "github.com/richseviora/huego/internal/client"
+	"github.com/richseviora/huego/pkg/logger"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/device"
+	"github.com/richseviora/huego/pkg/resources/room"
+	"github.com/richseviora/huego/pkg/resources/scene"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// fakeBridge serves the room, device, scene and behaviour endpoints MoveDevice uses.
+type fakeBridge struct {
+	mu      sync.Mutex
+	rooms   map[string]*room.RoomData
+	devices map[string]device.Data
+	scenes  map[string]*scene.SceneData
+	// afterUpdate is called with the lock held after a room is written, to simulate other clients.
+	afterUpdate func(id string)
+}
+
+func (f *fakeBridge) handler() http.Handler {
+	mux := http.NewServeMux()
+	write := func(w http.ResponseWriter, data interface{}) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{}, "data": data})
+	}
+	updated := func(w http.ResponseWriter, id, rtype string) {
+		write(w, []common.Reference{{RID: id, RType: rtype}})
+	}
+	mux.HandleFunc("GET /clip/v2/resource/device/{id}", func(w http.ResponseWriter, r *http.Request) {
+		write(w, []device.Data{f.devices[r.PathValue("id")]})
+	})
+	mux.HandleFunc("GET /clip/v2/resource/room", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		var result []room.RoomData
+		for _, id := range []string{"r1", "r2"} {
+			result = append(result, *f.rooms[id])
+		}
+		write(w, result)
+	})
+	mux.HandleFunc("GET /clip/v2/resource/room/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		write(w, []room.RoomData{*f.rooms[r.PathValue("id")]})
+	})
+	mux.HandleFunc("PUT /clip/v2/resource/room/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		var update room.RoomUpdate
+		_ = json.NewDecoder(r.Body).Decode(&update)
+		f.rooms[r.PathValue("id")].Children = *update.Children
+		if f.afterUpdate != nil {
+			f.afterUpdate(r.PathValue("id"))
+		}
+		updated(w, r.PathValue("id"), "room")
+	})
+	mux.HandleFunc("GET /clip/v2/resource/scene", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		var result []scene.SceneData
+		for _, id := range []string{"sc1", "sc2", "sc3"} {
+			if s, ok := f.scenes[id]; ok {
+				result = append(result, *s)
+			}
+		}
+		write(w, result)
+	})
+	mux.HandleFunc("PUT /clip/v2/resource/scene/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		var update scene.SceneUpdate
+		_ = json.NewDecoder(r.Body).Decode(&update)
+		f.scenes[r.PathValue("id")].Actions = update.Actions
+		updated(w, r.PathValue("id"), "scene")
+	})
+	mux.HandleFunc("DELETE /clip/v2/resource/scene/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.scenes, r.PathValue("id"))
+	})
+	mux.HandleFunc("GET /clip/v2/resource/behavior_instance", func(w http.ResponseWriter, r *http.Request) {
+		write(w, []string{})
+	})
+	return mux
+}
+
+func action(lightID string) scene.ActionTarget {
+	return scene.ActionTarget{Target: scene.Target{Rid: lightID, Rtype: "light"}, Action: scene.Action{On: &scene.On{On: true}}}
+}
+
+func TestMoveDevice(t *testing.T) {
+	bridge := &fakeBridge{
+		rooms: map[string]*room.RoomData{
+			"r1": {ID: "r1", Children: []common.Reference{{RID: "d1", RType: "device"}, {RID: "d2", RType: "device"}}},
+			"r2": {ID: "r2", Children: []common.Reference{{RID: "d3", RType: "device"}}},
+		},
+		devices: map[string]device.Data{
+			"d1": {ID: "d1", Services: []device.Services{{Rid: "l1", Rtype: "light"}}},
+		},
+		scenes: map[string]*scene.SceneData{
+			"sc1": {ID: "sc1", Group: common.Reference{RID: "r1", RType: "room"}, Actions: []scene.ActionTarget{action("l1"), action("l2")}},
+			"sc2": {ID: "sc2", Group: common.Reference{RID: "r1", RType: "room"}, Actions: []scene.ActionTarget{action("l1")}},
+			"sc3": {ID: "sc3", Group: common.Reference{RID: "r1", RType: "room"}, Actions: []scene.ActionTarget{action("l2")}},
+		},
+	}
+	server := httptest.NewServer(bridge.handler())
+	defer server.Close()
+	c := client2.NewAPIClient(server.URL, "key", logger.NoopLogger{})
+
+	result, err := MoveDevice(context.Background(), c, "d1", "r2", WithSceneCleanup())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &MoveResult{
+		SourceRoomID: "r1",
+		Attempts:     1,
+		Scenes: []AffectedScene{
+			{ID: "sc1", Outcome: SceneUpdated},
+			{ID: "sc2", Outcome: SceneDeleted},
+		},
+	}
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]common.Reference{{RID: "d2", RType: "device"}}, bridge.rooms["r1"].Children); diff != "" {
+		t.Errorf("source room mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]common.Reference{{RID: "d3", RType: "device"}, {RID: "d1", RType: "device"}}, bridge.rooms["r2"].Children); diff != "" {
+		t.Errorf("target room mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]scene.ActionTarget{action("l2")}, bridge.scenes["sc1"].Actions); diff != "" {
+		t.Errorf("scene mismatch (-want +got):\n%s", diff)
+	}
+	if _, ok := bridge.scenes["sc2"]; ok {
+		t.Errorf("scene sc2 was not deleted")
+	}
+}
+
+func TestMoveDevice_RetriesAfterStaleWrite(t *testing.T) {
+	stale := []common.Reference{{RID: "d1", RType: "device"}, {RID: "d2", RType: "device"}}
+	bridge := &fakeBridge{
+		rooms: map[string]*room.RoomData{
+			"r1": {ID: "r1", Children: stale},
+			"r2": {ID: "r2", Children: []common.Reference{{RID: "d3", RType: "device"}}},
+		},
+		devices: map[string]device.Data{
+			"d1": {ID: "d1", Services: []device.Services{{Rid: "l1", Rtype: "light"}}},
+		},
+	}
+	// Another client rewrites the source room with its old children once the device is in the target, leaving the
+	// device in both rooms.
+	rewritten := false
+	bridge.afterUpdate = func(id string) {
+		if id == "r2" && !rewritten {
+			rewritten = true
+			bridge.rooms["r1"].Children = stale
+		}
+	}
+	server := httptest.NewServer(bridge.handler())
+	defer server.Close()
+	c := client2.NewAPIClient(server.URL, "key", logger.NoopLogger{})
+
+	result, err := MoveDevice(context.Background(), c, "d1", "r2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&MoveResult{SourceRoomID: "r1", Attempts: 2}, result); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]common.Reference{{RID: "d2", RType: "device"}}, bridge.rooms["r1"].Children); diff != "" {
+		t.Errorf("source room mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]common.Reference{{RID: "d3", RType: "device"}, {RID: "d1", RType: "device"}}, bridge.rooms["r2"].Children); diff != "" {
+		t.Errorf("target room mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,345 @@
+package topology
+
+import (
+	"context"
+	"github.com/richseviora/huego/pkg/resources/client"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"sort"
+)
+
+type EdgeKind string
+
+const (
+	// EdgeService links a device to one of its services.
+	EdgeService EdgeKind = "service"
+	// EdgeOwner links a service to the device that owns it.
+	EdgeOwner EdgeKind = "owner"
+	// EdgeChild links a room or zone to a device or light it contains.
+	EdgeChild EdgeKind = "child"
+	// EdgeGroup links a scene, smart scene or behaviour to the room or zone it applies to.
+	EdgeGroup EdgeKind = "group"
+	// EdgeTarget links a scene to a light it sets, or a smart scene to a scene it recalls.
+	EdgeTarget EdgeKind = "target"
+	// EdgeSource links a behaviour to the device that triggers it.
+	EdgeSource EdgeKind = "source"
+	// EdgeRecall links a behaviour to a scene it recalls.
+	EdgeRecall EdgeKind = "recall"
+	// EdgeDependee links a behaviour to a resource the bridge lists as a dependency.
+	EdgeDependee EdgeKind = "dependee"
+)
+
+type Node struct {
+	Ref  common.Reference
+	Name string
+}
+
+type Edge struct {
+	From common.Reference
+	To   common.Reference
+	Kind EdgeKind
+}
+
+// Graph is a read-only view of the references between resources in a snapshot.
+type Graph struct {
+	nodes map[string]Node
+	out   map[string][]Edge
+	in    map[string][]Edge
+	// loaded holds the resource types present in the snapshot. Edges to other types are never dangling.
+	loaded map[string]bool
+}
+
+// Load reads a snapshot from the bridge and builds a graph from it.
+func Load(ctx context.Context, c client.HueServiceClient) (*Graph, error) {
+	snapshot, err := LoadSnapshot(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	return NewGraph(snapshot), nil
+}
+
+// NewGraph builds a graph from a snapshot.
+func NewGraph(s *Snapshot) *Graph {
+	g := &Graph{
+		nodes: make(map[string]Node),
+		out:   make(map[string][]Edge),
+		in:    make(map[string][]Edge),
+		loaded: map[string]bool{
+			"device": true, "light": true, "room": true, "zone": true, "scene": true, "smart_scene": true,
+			"behavior_instance": true, "motion": true, "zigbee_connectivity": true,
+		},
+	}
+	for _, d := range s.Devices {
+		ref := g.addNode("device", d.ID, d.Metadata.Name)
+		for _, service := range d.Services {
+			g.addEdge(ref, common.Reference{RID: service.Rid, RType: service.Rtype}, EdgeService)
+		}
+	}
+	for _, l := range s.Lights {
+		g.addEdge(g.addNode("light", l.ID, l.Metadata.Name), l.Owner, EdgeOwner)
+	}
+	for _, m := range s.Motion {
+		g.addEdge(g.addNode("motion", m.ID, ""), m.Owner, EdgeOwner)
+	}
+	for _, z := range s.ZigbeeConnectivity {
+		g.addEdge(g.addNode("zigbee_connectivity", z.ID, ""), z.Owner, EdgeOwner)
+	}
+	for _, r := range s.Rooms {
+		ref := g.addNode("room", r.ID, r.Metadata.Name)
+		for _, child := range r.Children {
+			g.addEdge(ref, child, EdgeChild)
+		}
+	}
+	for _, z := range s.Zones {
+		ref := g.addNode("zone", z.ID, z.Metadata.Name)
+		for _, child := range z.Children {
+			g.addEdge(ref, child, EdgeChild)
+		}
+	}
+	for _, sc := range s.Scenes {
+		ref := g.addNode("scene", sc.ID, sc.Metadata.Name)
+		g.addEdge(ref, sc.Group, EdgeGroup)
+		for _, a := range sc.Actions {
+			g.addEdge(ref, common.Reference{RID: a.Target.Rid, RType: a.Target.Rtype}, EdgeTarget)
+		}
+	}
+	for _, sc := range s.SmartScenes {
+		ref := g.addNode("smart_scene", sc.ID, sc.Metadata.Name)
+		g.addEdge(ref, sc.Group, EdgeGroup)
+		for _, week := range sc.WeekTimeslots {
+			for _, slot := range week.Timeslots {
+				g.addEdge(ref, slot.Target, EdgeTarget)
+			}
+		}
+	}
+	for _, b := range s.Behaviors {
+		ref := g.addNode("behavior_instance", b.ID, b.Metadata.Name)
+		if b.Configuration.Source.RID != "" {
+			g.addEdge(ref, b.Configuration.Source, EdgeSource)
+		}
+		for _, where := range b.Configuration.Where {
+			g.addEdge(ref, where.Group, EdgeGroup)
+		}
+		for _, slot := range b.Configuration.When.Timeslots {
+			for _, recall := range slot.OnMotion.RecallSingle {
+				g.addEdge(ref, recall.Action.Recall, EdgeRecall)
+			}
+		}
+		for _, dependee := range b.Dependees {
+			g.addEdge(ref, dependee.Target, EdgeDependee)
+		}
+	}
+	return g
+}
+
+func (g *Graph) addNode(rtype, id, name string) common.Reference {
+	ref := common.Reference{RID: id, RType: rtype}
+	g.nodes[id] = Node{Ref: ref, Name: name}
+	return ref
+}
+
+func (g *Graph) addEdge(from, to common.Reference, kind EdgeKind) {
+	if to.RID == "" {
+		return
+	}
+	e := Edge{From: from, To: to, Kind: kind}
+	g.out[from.RID] = append(g.out[from.RID], e)
+	g.in[to.RID] = append(g.in[to.RID], e)
+}
+
+// Node returns the node with the ID.
+func (g *Graph) Node(id string) (Node, bool) {
+	n, ok := g.nodes[id]
+	return n, ok
+}
+
+// Nodes returns every node of the resource type, sorted by name.
+func (g *Graph) Nodes(rtype string) []Node {
+	var result []Node
+	for _, n := range g.nodes {
+		if n.Ref.RType == rtype {
+			result = append(result, n)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].Ref.RID < result[j].Ref.RID
+	})
+	return result
+}
+
+// Outgoing returns the references from the resource.
+func (g *Graph) Outgoing(id string) []Edge {
+	return g.out[id]
+}
+
+// Incoming returns the references to the resource.
+func (g *Graph) Incoming(id string) []Edge {
+	return g.in[id]
+}
+
+func (g *Graph) outgoing(id string, kind EdgeKind) []common.Reference {
+	var result []common.Reference
+	for _, e := range g.out[id] {
+		if e.Kind == kind {
+			result = append(result, e.To)
+		}
+	}
+	return result
+}
+
+func (g *Graph) incoming(id string, kind EdgeKind) []common.Reference {
+	var result []common.Reference
+	for _, e := range g.in[id] {
+		if e.Kind == kind {
+			result = append(result, e.From)
+		}
+	}
+	return result
+}
+
+// LightsOfDevice returns the light services of the device.
+func (g *Graph) LightsOfDevice(deviceID string) []string {
+	var result []string
+	for _, ref := range g.outgoing(deviceID, EdgeService) {
+		if ref.RType == "light" {
+			result = append(result, ref.RID)
+		}
+	}
+	return result
+}
+
+// LightsInGroup returns the lights in a room or zone, following devices to their light services.
+func (g *Graph) LightsInGroup(groupID string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, child := range g.outgoing(groupID, EdgeChild) {
+		var ids []string
+		switch child.RType {
+		case "light":
+			ids = []string{child.RID}
+		case "device":
+			ids = g.LightsOfDevice(child.RID)
+		}
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				result = append(result, id)
+			}
+		}
+	}
+	return result
+}
+
+// DeviceOf returns the device ID for a device or one of its services.
+func (g *Graph) DeviceOf(id string) (string, bool) {
+	n, ok := g.nodes[id]
+	if ok && n.Ref.RType == "device" {
+		return id, true
+	}
+	for _, owner := range g.outgoing(id, EdgeOwner) {
+		return owner.RID, true
+	}
+	for _, device := range g.incoming(id, EdgeService) {
+		return device.RID, true
+	}
+	return "", false
+}
+
+// RoomOf returns the room containing a device, or the device owning a service such as a motion sensor.
+func (g *Graph) RoomOf(id string) (string, bool) {
+	deviceID, ok := g.DeviceOf(id)
+	if !ok {
+		return "", false
+	}
+	for _, group := range g.incoming(deviceID, EdgeChild) {
+		if group.RType == "room" {
+			return group.RID, true
+		}
+	}
+	return "", false
+}
+
+// ZonesOf returns the zones containing the light, either directly or through its device.
+func (g *Graph) ZonesOf(lightID string) []string {
+	var result []string
+	candidates := []string{lightID}
+	if deviceID, ok := g.DeviceOf(lightID); ok && deviceID != lightID {
+		candidates = append(candidates, deviceID)
+	}
+	for _, id := range candidates {
+		for _, group := range g.incoming(id, EdgeChild) {
+			if group.RType == "zone" {
+				result = append(result, group.RID)
+			}
+		}
+	}
+	return result
+}
+
+// Impact lists the resources that reference a device or its services.
+type Impact struct {
+	Groups      []common.Reference
+	Scenes      []common.Reference
+	SmartScenes []common.Reference
+	Behaviors   []common.Reference
+}
+
+// ImpactOfRemovingDevice returns the resources that would be left referencing the device or its services if it were
+// removed. Smart scenes are included when they recall an affected scene.
+func (g *Graph) ImpactOfRemovingDevice(deviceID string) Impact {
+	ids := []string{deviceID}
+	for _, service := range g.outgoing(deviceID, EdgeService) {
+		ids = append(ids, service.RID)
+	}
+	seen := make(map[string]bool)
+	var result Impact
+	for _, id := range ids {
+		for _, e := range g.in[id] {
+			if seen[e.From.RID] || e.From.RID == deviceID {
+				continue
+			}
+			seen[e.From.RID] = true
+			switch e.From.RType {
+			case "room", "zone":
+				result.Groups = append(result.Groups, e.From)
+			case "scene":
+				result.Scenes = append(result.Scenes, e.From)
+			case "behavior_instance":
+				result.Behaviors = append(result.Behaviors, e.From)
+			}
+		}
+	}
+	for _, s := range result.Scenes {
+		for _, e := range g.in[s.RID] {
+			if e.From.RType == "smart_scene" && !seen[e.From.RID] {
+				seen[e.From.RID] = true
+				result.SmartScenes = append(result.SmartScenes, e.From)
+			}
+		}
+	}
+	return result
+}
+
+// DanglingReferences returns every edge pointing at a resource of a loaded type that is not in the snapshot.
+func (g *Graph) DanglingReferences() []Edge {
+	var result []Edge
+	for _, edges := range g.out {
+		for _, e := range edges {
+			if !g.loaded[e.To.RType] {
+				continue
+			}
+			if _, ok := g.nodes[e.To.RID]; !ok {
+				result = append(result, e)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].From.RID != result[j].From.RID {
+			return result[i].From.RID < result[j].From.RID
+		}
+		return result[i].To.RID < result[j].To.RID
+	})
+	return result
+}
@@ -0,0 +1,106 @@
+package topology
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/richseviora/huego/pkg/resources/behavior_instance"
+	"github.com/richseviora/huego/pkg/resources/common"
+	"github.com/richseviora/huego/pkg/resources/device"
+	"github.com/richseviora/huego/pkg/resources/light"
+	"github.com/richseviora/huego/pkg/resources/motion"
+	"github.com/richseviora/huego/pkg/resources/room"
+	"github.com/richseviora/huego/pkg/resources/scene"
+	"github.com/richseviora/huego/pkg/resources/smart_scene"
+	"github.com/richseviora/huego/pkg/resources/zone"
+	"testing"
+)
+
+func testGraph() *Graph {
+	on := &scene.On{On: true}
+	return NewGraph(&Snapshot{
+		Devices: []device.Data{
+			{ID: "d1", Metadata: device.Metadata{Name: "Ceiling bulb"}, Services: []device.Services{{Rid: "l1", Rtype: "light"}}},
+			{ID: "d2", Metadata: device.Metadata{Name: "Lamp bulb"}, Services: []device.Services{{Rid: "l2", Rtype: "light"}}},
+			{ID: "s1", Metadata: device.Metadata{Name: "Sensor"}, Services: []device.Services{{Rid: "m1", Rtype: "motion"}, {Rid: "t1", Rtype: "temperature"}}},
+		},
+		Lights: []light.Light{
+			{ID: "l1", Owner: common.Reference{RID: "d1", RType: "device"}},
+			{ID: "l2", Owner: common.Reference{RID: "d2", RType: "device"}},
+		},
+		Motion: []motion.Data{{ID: "m1", Owner: common.Reference{RID: "s1", RType: "device"}}},
+		Rooms: []room.RoomData{
+			{ID: "r1", Metadata: room.RoomMetadata{Name: "Kitchen"}, Children: []common.Reference{
+				{RID: "d1", RType: "device"}, {RID: "d2", RType: "device"}, {RID: "s1", RType: "device"},
+			}},
+		},
+		Zones: []zone.ZoneData{
+			{ID: "z1", Children: []common.Reference{{RID: "l2", RType: "light"}, {RID: "l9", RType: "light"}}},
+		},
+		Scenes: []scene.SceneData{
+			{ID: "sc1", Group: common.Reference{RID: "r1", RType: "room"}, Actions: []scene.ActionTarget{
+				{Target: scene.Target{Rid: "l1", Rtype: "light"}, Action: scene.Action{On: on}},
+			}},
+			{ID: "sc2", Group: common.Reference{RID: "z1", RType: "zone"}, Actions: []scene.ActionTarget{
+				{Target: scene.Target{Rid: "l2", Rtype: "light"}, Action: scene.Action{On: on}},
+			}},
+		},
+		SmartScenes: []smart_scene.Data{
+			{ID: "ss1", Group: common.Reference{RID: "r1", RType: "room"}, WeekTimeslots: []smart_scene.WeekTimeslots{
+				{Timeslots: []smart_scene.Timeslot{{Target: common.Reference{RID: "sc1", RType: "scene"}}}},
+			}},
+		},
+		Behaviors: []behavior_instance.Data{
+			{ID: "b1", Configuration: behavior_instance.Configuration{
+				Source: common.Reference{RID: "s1", RType: "device"},
+				Where:  []behavior_instance.Where{{Group: common.Reference{RID: "r1", RType: "room"}}},
+			}},
+		},
+	})
+}
+
+func TestGraph_Queries(t *testing.T) {
+	g := testGraph()
+	if diff := cmp.Diff([]string{"l1", "l2"}, g.LightsInGroup("r1")); diff != "" {
+		t.Errorf("LightsInGroup(r1) mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"l2", "l9"}, g.LightsInGroup("z1")); diff != "" {
+		t.Errorf("LightsInGroup(z1) mismatch (-want +got):\n%s", diff)
+	}
+	for _, id := range []string{"m1", "t1", "s1"} {
+		if roomID, ok := g.RoomOf(id); !ok || roomID != "r1" {
+			t.Errorf("RoomOf(%s) = %s, %v, want r1", id, roomID, ok)
+		}
+	}
+	if diff := cmp.Diff([]string{"z1"}, g.ZonesOf("l2")); diff != "" {
+		t.Errorf("ZonesOf(l2) mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGraph_ImpactOfRemovingDevice(t *testing.T) {
+	g := testGraph()
+	expected := Impact{
+		Groups:      []common.Reference{{RID: "r1", RType: "room"}},
+		Scenes:      []common.Reference{{RID: "sc1", RType: "scene"}},
+		SmartScenes: []common.Reference{{RID: "ss1", RType: "smart_scene"}},
+	}
+	if diff := cmp.Diff(expected, g.ImpactOfRemovingDevice("d1")); diff != "" {
+		t.Errorf("ImpactOfRemovingDevice(d1) mismatch (-want +got):\n%s", diff)
+	}
+	expected = Impact{
+		Groups:    []common.Reference{{RID: "r1", RType: "room"}},
+		Behaviors: []common.Reference{{RID: "b1", RType: "behavior_instance"}},
+	}
+	if diff := cmp.Diff(expected, g.ImpactOfRemovingDevice("s1")); diff != "" {
+		t.Errorf("ImpactOfRemovingDevice(s1) mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGraph_DanglingReferences(t *testing.T) {
+	expected := []Edge{{
+		From: common.Reference{RID: "z1", RType: "zone"},
+		To:   common.Reference{RID: "l9", RType: "light"},
+		Kind: EdgeChild,
+	}}
+	if diff := cmp.Diff(expected, testGraph().DanglingReferences()); diff != "" {
+		t.Errorf("DanglingReferences() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,98 @@
+package topology
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/richseviora/huego/pkg/resources/behavior_instance"
+	"github.com/richseviora/huego/pkg/resources/client"
+	"github.com/richseviora/huego/pkg/resources/device"
+	"github.com/richseviora/huego/pkg/resources/light"
+	"github.com/richseviora/huego/pkg/resources/motion"
+	"github.com/richseviora/huego/pkg/resources/room"
+	"github.com/richseviora/huego/pkg/resources/scene"
+	"github.com/richseviora/huego/pkg/resources/smart_scene"
+	"github.com/richseviora/huego/pkg/resources/zigbee_connectivity"
+	"github.com/richseviora/huego/pkg/resources/zone"
+	"io"
+	"time"
+)
+
+// Snapshot is a point in time copy of the bridge resources the graph is built from. It can be saved and read back to
+// query a home offline.
+type Snapshot struct {
+	Taken              time.Time                  `json:"taken"`
+	Devices            []device.Data              `json:"devices"`
+	Lights             []light.Light              `json:"lights"`
+	Rooms              []room.RoomData            `json:"rooms"`
+	Zones              []zone.ZoneData            `json:"zones"`
+	Scenes             []scene.SceneData          `json:"scenes"`
+	SmartScenes        []smart_scene.Data         `json:"smart_scenes"`
+	Behaviors          []behavior_instance.Data   `json:"behaviors"`
+	Motion             []motion.Data              `json:"motion"`
+	ZigbeeConnectivity []zigbee_connectivity.Data `json:"zigbee_connectivity"`
+}
+
+// LoadSnapshot reads every resource type the graph understands from the bridge.
+func LoadSnapshot(ctx context.Context, c client.HueServiceClient) (*Snapshot, error) {
+	result := &Snapshot{Taken: time.Now()}
+	devices, err := c.DeviceService().GetAllDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.Devices = devices.Data
+	lights, err := c.LightService().GetAllLights(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.Lights = lights.Data
+	rooms, err := c.RoomService().GetAllRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.Rooms = rooms.Data
+	zones, err := c.ZoneService().GetAllZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.Zones = zones.Data
+	scenes, err := c.SceneService().GetAllScenes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.Scenes = scenes.Data
+	smartScenes, err := c.SmartSceneService().GetAllSmartScenes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.SmartScenes = smartScenes.Data
+	behaviors, err := c.BehaviorInstanceService().GetAllBehaviorInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.Behaviors = behaviors.Data
+	motions, err := c.MotionService().GetAllMotion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.Motion = motions.Data
+	connectivity, err := c.ZigbeeConnectivityService().GetAllZigbeeConnectivity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.ZigbeeConnectivity = connectivity.Data
+	return result, nil
+}
+
+// Write encodes the snapshot as JSON.
+func (s *Snapshot) Write(w io.Writer) error {
+	return json.NewEncoder(w).Encode(s)
+}
+
+// ReadSnapshot decodes a snapshot written by Snapshot.Write.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	var result Snapshot
+	if err := json.NewDecoder(r).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}